@@ -0,0 +1,111 @@
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/cbrgm/promcheck/internal/checker"
+	"github.com/cbrgm/promcheck/internal/report"
+)
+
+// baselineFile represents the content of a baseline file.
+type baselineFile struct {
+	// Findings represents the recorded selectors without a result value
+	Findings []report.BaselineEntry `json:"findings"`
+}
+
+// baseline represents the findings recorded in a baseline file. Strict mode
+// only fails on findings not recorded in it.
+type baseline map[report.BaselineEntry]struct{}
+
+// loadBaseline reads the baseline file at path.
+func loadBaseline(path string) (baseline, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var f baselineFile
+	if err := json.Unmarshal(raw, &f); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	b := make(baseline, len(f.Findings))
+	for _, e := range f.Findings {
+		b[e] = struct{}{}
+	}
+	return b, nil
+}
+
+// writeBaseline records the findings of the given check results in a baseline file at path.
+func writeBaseline(path string, results []checker.CheckResult) error {
+	raw, err := json.MarshalIndent(baselineFile{Findings: baselineFindings(results)}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(raw, '\n'), 0o644)
+}
+
+// baselineFindings returns the selectors without a result value of the given
+// check results as baseline entries, sorted so baseline files diff cleanly.
+func baselineFindings(results []checker.CheckResult) []report.BaselineEntry {
+	findings := []report.BaselineEntry{}
+	for _, cr := range results {
+		for _, selector := range cr.NoResults {
+			findings = append(findings, baselineEntry(cr, selector))
+		}
+	}
+	slices.SortFunc(findings, compareBaselineEntries)
+	return slices.Compact(findings)
+}
+
+// known returns the selectors without a result value of the check result recorded in the baseline.
+func (b baseline) known(cr checker.CheckResult) []string {
+	var known []string
+	for _, selector := range cr.NoResults {
+		if _, ok := b[baselineEntry(cr, selector)]; ok {
+			known = append(known, selector)
+		}
+	}
+	return known
+}
+
+// fixed returns the baseline entries of the checked rules which are no
+// longer findings. Entries of rules which were not checked, e.g. because
+// they were filtered out or failed with an error, are never fixed.
+func (b baseline) fixed(results []checker.CheckResult) []report.BaselineEntry {
+	type ruleKey struct{ file, group, rule string }
+	checked := map[ruleKey]bool{}
+	findings := map[report.BaselineEntry]bool{}
+	for _, cr := range results {
+		if cr.Error != nil || cr.Unchecked {
+			continue
+		}
+		checked[ruleKey{cr.File, cr.Group, cr.Name}] = true
+		for _, selector := range cr.NoResults {
+			findings[baselineEntry(cr, selector)] = true
+		}
+	}
+	var fixed []report.BaselineEntry
+	for e := range b {
+		if checked[ruleKey{e.File, e.Group, e.Rule}] && !findings[e] {
+			fixed = append(fixed, e)
+		}
+	}
+	slices.SortFunc(fixed, compareBaselineEntries)
+	return fixed
+}
+
+func baselineEntry(cr checker.CheckResult, selector string) report.BaselineEntry {
+	return report.BaselineEntry{File: cr.File, Group: cr.Group, Rule: cr.Name, Selector: selector}
+}
+
+func compareBaselineEntries(a, b report.BaselineEntry) int {
+	return cmp.Or(
+		cmp.Compare(a.File, b.File),
+		cmp.Compare(a.Group, b.Group),
+		cmp.Compare(a.Rule, b.Rule),
+		cmp.Compare(a.Selector, b.Selector),
+	)
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cbrgm/promcheck/internal/checker"
+	"github.com/cbrgm/promcheck/internal/report"
+)
+
+func TestBaseline_WriteAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	results := []checker.CheckResult{
+		{File: "b.yaml", Group: "g", Name: "r", NoResults: []string{`foo`, `bar{job="x"}`}},
+		{File: "a.yaml", Group: "g", Name: "r", NoResults: []string{`baz`}, Results: []string{`up`}},
+		{File: "a.yaml", Group: "g", Name: "ok", Results: []string{`up`}},
+	}
+	require.NoError(t, writeBaseline(path, results))
+
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"findings": [
+		{"file": "a.yaml", "group": "g", "rule": "r", "selector": "baz"},
+		{"file": "b.yaml", "group": "g", "rule": "r", "selector": "bar{job=\"x\"}"},
+		{"file": "b.yaml", "group": "g", "rule": "r", "selector": "foo"}
+	]}`, string(raw))
+
+	b, err := loadBaseline(path)
+	require.NoError(t, err)
+	require.Len(t, b, 3)
+	require.Equal(t, []string{`foo`}, b.known(checker.CheckResult{File: "b.yaml", Group: "g", Name: "r", NoResults: []string{`foo`, `qux`}}))
+	require.Empty(t, b.known(checker.CheckResult{File: "b.yaml", Group: "other", Name: "r", NoResults: []string{`foo`}}))
+}
+
+func TestBaseline_WritesEmptyFindings(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	require.NoError(t, writeBaseline(path, nil))
+	raw, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"findings": []}`, string(raw))
+}
+
+func TestLoadBaseline_Errors(t *testing.T) {
+	_, err := loadBaseline(filepath.Join(t.TempDir(), "missing.json"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	require.NoError(t, os.WriteFile(path, []byte("findings: []"), 0o600))
+	_, err = loadBaseline(path)
+	require.Error(t, err)
+}
+
+func TestBaseline_Fixed(t *testing.T) {
+	b := baseline{
+		{File: "f.yaml", Group: "g", Rule: "r", Selector: `foo`}:       {},
+		{File: "f.yaml", Group: "g", Rule: "r", Selector: `bar`}:       {},
+		{File: "f.yaml", Group: "g", Rule: "broken", Selector: `baz`}:  {},
+		{File: "f.yaml", Group: "g", Rule: "removed", Selector: `qux`}: {},
+	}
+	fixed := b.fixed([]checker.CheckResult{
+		{File: "f.yaml", Group: "g", Name: "r", NoResults: []string{`foo`}, Results: []string{`bar`}},
+		{File: "f.yaml", Group: "g", Name: "broken", Error: errors.New("parse error")},
+	})
+	require.Equal(t, []report.BaselineEntry{{File: "f.yaml", Group: "g", Rule: "r", Selector: `bar`}}, fixed)
+}
+
+func TestRunCheck_StrictModeIgnoresBaselinedFindings(t *testing.T) {
+	rep := &fakeReporter{}
+	fc := &fakeChecker{res: []checker.CheckResult{{File: "f.yaml", Group: "g", Name: "r", NoResults: []string{`foo`}, Results: []string{`bar`}}}}
+	app := &promcheckApp{
+		check:         fc,
+		report:        rep,
+		logger:        newTestLogger(),
+		optStrictMode: true,
+		baseline: baseline{
+			{File: "f.yaml", Group: "g", Rule: "r", Selector: `foo`}: {},
+			{File: "f.yaml", Group: "g", Rule: "r", Selector: `bar`}: {},
+		},
+	}
+	src := staticSource{groups: []checker.RuleGroup{{Name: "g", Rules: []checker.Rule{{Name: "r", Expression: "foo and bar"}}}}}
+	require.NoError(t, app.runCheck(t.Context(), src), "baselined findings must not fail strict runs")
+	require.Equal(t, []report.BaselineEntry{{File: "f.yaml", Group: "g", Rule: "r", Selector: `bar`}}, rep.fixed)
+
+	fc.res[0].NoResults = []string{`foo`, `new`}
+	require.ErrorIs(t, app.runCheck(t.Context(), src), ErrStrictFindings, "new findings must fail strict runs")
+}
+
+func TestRunCheck_WritesBaseline(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	app := &promcheckApp{
+		check:            &fakeChecker{res: []checker.CheckResult{{File: "f.yaml", Group: "g", Name: "r", NoResults: []string{`foo`}}}},
+		report:           &fakeReporter{},
+		logger:           newTestLogger(),
+		optStrictMode:    true,
+		optBaselineWrite: path,
+	}
+	src := staticSource{groups: []checker.RuleGroup{{Name: "g", Rules: []checker.Rule{{Name: "r", Expression: "foo"}}}}}
+	require.ErrorIs(t, app.runCheck(t.Context(), src), ErrStrictFindings)
+
+	b, err := loadBaseline(path)
+	require.NoError(t, err)
+	require.Equal(t, baseline{{File: "f.yaml", Group: "g", Rule: "r", Selector: `foo`}: {}}, b)
+}
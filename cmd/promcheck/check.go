@@ -6,6 +6,7 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
 	"net/http"
 	"os"
 	"os/signal"
@@ -31,19 +32,34 @@ import (
 var ErrNoRuleGroups = errors.New("no rule groups to check")
 
 // ErrStrictFindings is returned by runCheck when --strict is set and one or
-// more selectors had no results. In exporter mode this sentinel is swallowed
+// more selectors had no results, or were probed from a partial response with
+// --strict.partial-response. In exporter mode this sentinel is swallowed
 // (runCheck returns nil instead) so a dead rule can't kill the exporter loop.
 var ErrStrictFindings = errors.New("strict: selectors without results found")
 
+// ErrCheckTimeout is returned by runCheck when --check.timeout is exceeded
+// before all rules were checked. It is returned once the report, listing the
+// unchecked rules, is printed.
+var ErrCheckTimeout = errors.New("check timeout exceeded")
+
+// ErrRuleErrors is returned by runCheck when --check.continue-on-error is set
+// and one or more rules could not be checked. Like ErrStrictFindings, it is
+// only returned once the full report is printed, and swallowed in exporter mode.
+var ErrRuleErrors = errors.New("rules could not be checked")
+
 type Reporter interface {
 	Dump() error
-	AddSection(file, group, name, expression string, failed, success []string)
+	AddSection(file, group, name, expression string, failed, success []string, opts ...report.SectionOption)
 	AddTotalCheckedGroups(count int)
+	AddProbeRetries(count int)
+	AddBaselineFixed(entries ...report.BaselineEntry)
 }
 
 type Checker interface {
+	StartRun(groups ...checker.RuleGroup)
 	CheckRuleGroup(ctx context.Context, group checker.RuleGroup) ([]checker.CheckResult, error)
 	IsIgnoredGroup(name string) bool
+	ProbeStats() checker.ProbeStats
 }
 
 type promcheckApp struct {
@@ -58,7 +74,12 @@ type promcheckApp struct {
 	optInlineExpressions            []string
 	optCheckMatch                   []string
 	optStrictMode                   bool
+	optStrictPartialResponse        bool
+	optCheckTimeout                 time.Duration
+	optBaselineWrite                string
 
+	filter       ruleFilter
+	baseline     baseline
 	check        Checker
 	report       Reporter
 	logger       *slog.Logger
@@ -91,6 +112,21 @@ func newPromcheck(config *config, logger *slog.Logger) (*promcheckApp, error) {
 		return nil, err
 	}
 
+	filter, err := newRuleFilter(config.CheckRuleType, config.CheckRuleLabel)
+	if err != nil {
+		logger.Error("configuration error", "err", err)
+		return nil, err
+	}
+
+	var known baseline
+	if config.Baseline != "" {
+		known, err = loadBaseline(config.Baseline)
+		if err != nil {
+			logger.Error("failed to load baseline", "err", err)
+			return nil, err
+		}
+	}
+
 	promAPI := prometheusv1.NewAPI(client)
 	rulesChecker, err := checker.NewPrometheusRulesChecker(
 		checker.PrometheusRulesCheckerConfig{
@@ -98,6 +134,29 @@ func newPromcheck(config *config, logger *slog.Logger) (*promcheckApp, error) {
 			IgnoredSelectorsRegexp: config.CheckIgnoredSelectorsRegexp,
 			IgnoredGroupsRegexp:    config.CheckIgnoredGroupsRegexp,
 			MaxConcurrency:         config.CheckConcurrency,
+			QPS:                    config.CheckQPS,
+			Burst:                  config.CheckBurst,
+			AdaptiveQPS:            config.CheckQPSAdaptive,
+			Prober:                 config.CheckProber,
+			BatchSize:              config.CheckBatchSize,
+			DrillDown:              config.CheckDrillDown,
+			Suggestions:            config.CheckSuggest,
+			Alternations:           config.CheckAlternations,
+			GroupingLabels:         config.CheckGroupingLabels,
+			Joins:                  config.CheckJoins,
+			RecordingRules:         config.CheckRecordingRules,
+			Evaluate:               config.CheckEvaluate,
+			Backtest:               config.CheckBacktest,
+			BacktestStep:           config.CheckBacktestStep,
+			Lookback:               config.CheckLookback,
+			MaxAge:                 config.CheckMaxAge,
+			MaxSeries:              config.CheckMaxSeries,
+			MaxSeriesSelectors:     config.CheckMaxSeriesSelector,
+			QueryTimeout:           config.CheckQueryTimeout,
+			SeriesLimit:            config.CheckSeriesLimit,
+			Retries:                config.CheckRetries,
+			RetryBackoff:           config.CheckRetryBackoff,
+			ContinueOnError:        config.CheckContinueOnError,
 		},
 		promAPI,
 	)
@@ -140,8 +199,13 @@ func newPromcheck(config *config, logger *slog.Logger) (*promcheckApp, error) {
 		optInlineExpressions:            config.CheckExpressions,
 		optCheckMatch:                   config.CheckMatch,
 		optStrictMode:                   config.StrictMode,
+		optStrictPartialResponse:        config.StrictPartialResponse,
+		optCheckTimeout:                 config.CheckTimeout,
+		optBaselineWrite:                config.BaselineWrite,
 
 		// internal
+		filter:       filter,
+		baseline:     known,
 		check:        rulesChecker,
 		report:       reporter,
 		logger:       logger,
@@ -183,6 +247,12 @@ type ruleSource interface {
 // runCheck loads rule groups from src, probes them concurrently, aggregates the
 // results into the report, and handles strict mode. It is shared by all check modes.
 func (app *promcheckApp) runCheck(ctx context.Context, src ruleSource) error {
+	if app.optCheckTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, app.optCheckTimeout)
+		defer cancel()
+	}
+
 	groups, err := src.load(ctx)
 	if err != nil {
 		return err
@@ -192,11 +262,20 @@ func (app *promcheckApp) runCheck(ctx context.Context, src ruleSource) error {
 		return ErrNoRuleGroups
 	}
 
+	// Recording rules of ignored or filtered out groups are still resolved by
+	// the selectors referring to them, so keep all loaded groups for the run.
+	loaded := slices.Clone(groups)
+
 	// Filter out ignored groups up front so they are neither probed, nor
 	// counted, nor rendered.
 	groups = slices.DeleteFunc(groups, func(g checker.RuleGroup) bool {
 		return app.check.IsIgnoredGroup(g.Name)
 	})
+	groups = app.filter.apply(groups)
+	if len(groups) == 0 {
+		app.logger.Error("no rule groups left to check after ignoring and filtering rules", "source", src.name())
+		return ErrNoRuleGroups
+	}
 
 	var (
 		mu           sync.Mutex
@@ -204,6 +283,10 @@ func (app *promcheckApp) runCheck(ctx context.Context, src ruleSource) error {
 	)
 	eg, ctx := errgroup.WithContext(ctx)
 
+	// All groups of a run are evaluated at the same timestamp and share a
+	// probe cache, so selectors referenced by many rules are probed once.
+	app.check.StartRun(loaded...)
+
 	// The outer fan-out over groups is unbounded; total probe concurrency is
 	// bounded inside the checker (see PrometheusRulesCheckerConfig.MaxConcurrency).
 	for _, group := range groups {
@@ -219,13 +302,50 @@ func (app *promcheckApp) runCheck(ctx context.Context, src ruleSource) error {
 			return nil
 		})
 	}
-	if err := eg.Wait(); err != nil {
+	err = eg.Wait()
+	stats := app.recordProbeStats()
+	if err != nil {
 		return err
 	}
 	app.report.AddTotalCheckedGroups(len(groups))
+	for _, retries := range stats.Retries {
+		app.report.AddProbeRetries(retries)
+	}
 
-	hasExpressionsWithoutResult := false
+	findings := app.reportResults(checkResults)
+	if err := app.updateBaseline(checkResults); err != nil {
+		return err
+	}
+	return app.finishRun(findings)
+}
+
+// runFindings represents what the check results of a run found, deciding
+// the outcome of the run.
+type runFindings struct {
+	// expressionsWithoutResult reports whether any selector without a result value isn't known from the baseline
+	expressionsWithoutResult bool
+
+	// partialResponses reports whether any selector was probed from a partial response
+	partialResponses bool
+
+	// ruleErrors reports whether any rule failed to be checked because of an error
+	ruleErrors bool
+
+	// uncheckedRules reports whether any rule was not checked before the run's deadline
+	uncheckedRules bool
+}
+
+// reportResults adds the given check results to the report, matching their
+// selectors without a result value against the baseline, logs rule errors
+// and partial responses, and returns what the results found.
+func (app *promcheckApp) reportResults(checkResults []checker.CheckResult) runFindings {
+	var findings runFindings
 	for _, cr := range checkResults {
+		opts := sectionOptions(cr)
+		known := app.baseline.known(cr)
+		if len(known) > 0 {
+			opts = append(opts, report.WithBaselined(known...))
+		}
 		app.report.AddSection(
 			cr.File,
 			cr.Group,
@@ -233,23 +353,202 @@ func (app *promcheckApp) runCheck(ctx context.Context, src ruleSource) error {
 			cr.Expression,
 			cr.NoResults,
 			cr.Results,
+			opts...,
 		)
-		if len(cr.NoResults) > 0 {
-			hasExpressionsWithoutResult = true
+		if len(cr.NoResults) > len(known) {
+			findings.expressionsWithoutResult = true
+		}
+		if cr.Error != nil {
+			app.logger.Warn("failed to check rule", "file", cr.File, "group", cr.Group, "rule", cr.Name, "err", cr.Error)
+			findings.ruleErrors = true
+		}
+		findings.uncheckedRules = findings.uncheckedRules || cr.Unchecked
+		for _, s := range cr.Selectors {
+			if s.PartialResponse {
+				app.logger.Warn("probed selector from a partial response", "file", cr.File, "group", cr.Group, "rule", cr.Name, "selector", s.Selector, "warnings", s.Warnings)
+				findings.partialResponses = true
+			}
+		}
+	}
+	return findings
+}
+
+// updateBaseline adds the baseline entries the given check results fixed to
+// the report, and writes their findings to a new baseline file if requested.
+func (app *promcheckApp) updateBaseline(checkResults []checker.CheckResult) error {
+	if app.baseline != nil {
+		app.report.AddBaselineFixed(app.baseline.fixed(checkResults)...)
+	}
+	if app.optBaselineWrite == "" {
+		return nil
+	}
+	if err := writeBaseline(app.optBaselineWrite, checkResults); err != nil {
+		return fmt.Errorf("failed to write baseline: %w", err)
+	}
+	app.logger.Info("wrote baseline", "file", app.optBaselineWrite)
+	return nil
+}
+
+// finishRun prints the report and returns the outcome of the run given its
+// findings: nil, or one of the ErrCheckTimeout, ErrRuleErrors and
+// ErrStrictFindings sentinels.
+func (app *promcheckApp) finishRun(findings runFindings) error {
+	strictFindings := app.optStrictMode && (findings.expressionsWithoutResult || findings.partialResponses && app.optStrictPartialResponse)
+	if !strictFindings && !findings.ruleErrors && !findings.uncheckedRules {
+		return app.report.Dump()
+	}
+	if err := app.report.Dump(); err != nil {
+		app.logger.Error("failed to print report", "err", err)
+	}
+	if findings.uncheckedRules {
+		// unlike findings, an incomplete run is a failure in exporter mode, too
+		app.logger.Error("check timeout exceeded before all rules were checked", "timeout", app.optCheckTimeout)
+		return ErrCheckTimeout
+	}
+	if app.optExporterModeEnabled {
+		// The exporter is a long-running process; a dead or broken rule
+		// must not kill it, so the sentinels are swallowed here.
+		return nil
+	}
+	if findings.ruleErrors {
+		// an incomplete check outweighs the findings of the checked rules
+		return ErrRuleErrors
+	}
+	return ErrStrictFindings
+}
+
+// recordProbeStats logs the probe cache, retry and rate limit statistics of the last run,
+// adds them to the exporter metrics and returns them.
+func (app *promcheckApp) recordProbeStats() checker.ProbeStats {
+	stats := app.check.ProbeStats()
+	app.logger.Debug("probe cache statistics",
+		"hits", stats.Hits,
+		"shared", stats.Shared,
+		"misses", stats.Misses,
+		"batches", stats.Batches,
+		"batch_fallbacks", stats.BatchFallbacks,
+	)
+	if stats.RateBackoffs > 0 {
+		app.logger.Info("backed off the query rate as Prometheus slowed down", "backoffs", stats.RateBackoffs)
+	}
+	for class, retries := range stats.Retries {
+		app.logger.Info("retried failed queries", "class", class, "retries", retries)
+	}
+	if app.metrics == nil {
+		return stats
+	}
+	app.metrics.AddProbeCacheLookups("hit", float64(stats.Hits))
+	app.metrics.AddProbeCacheLookups("shared", float64(stats.Shared))
+	app.metrics.AddProbeCacheLookups("miss", float64(stats.Misses))
+	for class, retries := range stats.Retries {
+		app.metrics.AddProbeRetries(class, float64(retries))
+	}
+	return stats
+}
+
+// sectionOptions translates the rule status and per-selector findings of cr into report section options.
+func sectionOptions(cr checker.CheckResult) []report.SectionOption {
+	var opts []report.SectionOption
+	ruleType := report.RuleTypeRecord
+	if cr.Alerting {
+		ruleType = report.RuleTypeAlert
+	}
+	opts = append(opts, report.WithRuleMetadata(report.RuleMetadata{
+		Type:          ruleType,
+		Interval:      cr.Interval,
+		For:           cr.For,
+		KeepFiringFor: cr.KeepFiringFor,
+		Labels:        cr.Labels,
+		Annotations:   cr.Annotations,
+	}))
+	if cr.Error != nil {
+		opts = append(opts, report.WithError(cr.Error.Error()))
+	}
+	if cr.Unchecked {
+		opts = append(opts, report.WithUnchecked())
+	}
+	if cr.Status != "" {
+		opts = append(opts, report.WithStatus(string(cr.Status), cr.Series))
+	}
+	if bt := cr.Backtest; bt != nil {
+		opts = append(opts, report.WithBacktest(report.Backtest{
+			RangeSeconds:   bt.Range.Seconds(),
+			StepSeconds:    bt.Step.Seconds(),
+			Pending:        bt.Pending,
+			Firing:         bt.Firing,
+			PendingSeconds: bt.PendingDuration.Seconds(),
+			FiringSeconds:  bt.FiringDuration.Seconds(),
+			FiringRatio:    bt.FiringRatio,
+			NeverFired:     bt.NeverFired,
+			Continuous:     bt.Continuous,
+		}))
+	}
+	for _, j := range cr.EmptyJoins {
+		opts = append(opts, report.WithEmptyJoins(report.EmptyJoin{
+			Expression: j.Expression,
+			Matching:   j.Matching,
+			LHS:        j.LHS,
+			RHS:        j.RHS,
+			LHSExample: j.LHSExample,
+			RHSExample: j.RHSExample,
+		}))
+	}
+	if len(cr.ExpectedEmpty) > 0 {
+		opts = append(opts, report.WithExpectedEmpty(cr.ExpectedEmpty...))
+	}
+	for _, s := range cr.Suppressed {
+		suppressed := report.SuppressedSelector{Selector: s.Selector, Reason: s.Reason}
+		if !s.Expires.IsZero() {
+			expires := s.Expires
+			suppressed.Expires = &expires
 		}
+		opts = append(opts, report.WithSuppressed(suppressed))
+	}
+	if len(cr.Selectors) == 0 {
+		return opts
 	}
-	if hasExpressionsWithoutResult && app.optStrictMode {
-		if err := app.report.Dump(); err != nil {
-			app.logger.Error("failed to print report", "err", err)
+	details := make([]report.SelectorDetail, 0, len(cr.Selectors))
+	for _, s := range cr.Selectors {
+		detail := report.SelectorDetail{
+			Selector:             s.Selector,
+			Presence:             string(s.Presence),
+			Stale:                s.Stale,
+			NotSeenWithinSeconds: s.NotSeenWithin.Seconds(),
+			NotRecorded:          s.NotRecorded,
+			DependencyChains:     s.DependencyChains,
+			Series:               s.Series,
+			SeriesLimit:          s.SeriesLimit,
+			MissingLabels:        missingLabels(s.MissingLabels),
+			DeadAlternatives:     s.DeadAlternatives,
+			Warnings:             s.Warnings,
+			PartialResponse:      s.PartialResponse,
 		}
-		if app.optExporterModeEnabled {
-			// The exporter is a long-running process; a dead rule must not
-			// kill it, so the sentinel is swallowed here.
-			return nil
+		if !s.LastSeen.IsZero() {
+			lastSeen := s.LastSeen
+			detail.LastSeen = &lastSeen
+		}
+		if s.Diagnosis != nil {
+			detail.Diagnosis = &report.Diagnosis{
+				MetricMissing: s.Diagnosis.MetricMissing,
+				Culprits:      s.Diagnosis.Culprits,
+				Suggestions:   s.Diagnosis.Suggestions,
+			}
 		}
-		return ErrStrictFindings
+		details = append(details, detail)
 	}
-	return app.report.Dump()
+	return append(opts, report.WithSelectorDetails(details...))
+}
+
+// missingLabels translates the missing grouping and matching labels of a selector into their report form.
+func missingLabels(missing []checker.MissingLabel) []report.MissingLabel {
+	if len(missing) == 0 {
+		return nil
+	}
+	out := make([]report.MissingLabel, 0, len(missing))
+	for _, m := range missing {
+		out = append(out, report.MissingLabel{Label: m.Label, Series: m.Series, Total: m.Total})
+	}
+	return out
 }
 
 // fileSource loads rule groups from rule files matched by a glob pattern.
@@ -284,31 +583,67 @@ func (app *promcheckApp) checkRulesFromRuleFiles(ctx context.Context) error {
 }
 
 func processFile(p promql.Parser, logger *slog.Logger, file string) ([]checker.RuleGroup, error) {
-	ruleGroups, errs := rulefmt.ParseFile(file, false, model.UTF8Validation, p, logger)
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	ruleGroups, errs := rulefmt.Parse(content, false, model.UTF8Validation, p, logger)
 	if len(errs) > 0 {
 		return nil, fmt.Errorf("%s: %w", file, errors.Join(errs...))
 	}
+	suppressions, err := parseFileSuppressions(content)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
 
 	converted := make([]checker.RuleGroup, 0, len(ruleGroups.Groups))
-	for _, group := range ruleGroups.Groups {
-		converted = append(converted, rulefmtToPromcheck(file, group))
+	for i, group := range ruleGroups.Groups {
+		g := rulefmtToPromcheck(file, group)
+		for j := range g.Rules {
+			g.Rules[j].Suppressions = suppressions.rule(i, j)
+		}
+		converted = append(converted, g)
 	}
 	return converted, nil
 }
 
 func rulefmtToPromcheck(fileName string, group rulefmt.RuleGroup) checker.RuleGroup {
-	out := checker.RuleGroup{Name: group.Name, File: fileName, Rules: make([]checker.Rule, 0, len(group.Rules))}
+	out := checker.RuleGroup{
+		Name:     group.Name,
+		File:     fileName,
+		Interval: time.Duration(group.Interval),
+		Rules:    make([]checker.Rule, 0, len(group.Rules)),
+	}
 	if group.QueryOffset != nil {
 		// model.Duration is a typedef of time.Duration.
 		out.QueryOffset = time.Duration(*group.QueryOffset)
 	}
 	for _, rule := range group.Rules {
 		name := cmp.Or(rule.Record, rule.Alert)
-		out.Rules = append(out.Rules, checker.Rule{Name: name, Expression: rule.Expr})
+		out.Rules = append(out.Rules, checker.Rule{
+			Name:          name,
+			Expression:    rule.Expr,
+			Alerting:      rule.Alert != "",
+			For:           time.Duration(rule.For),
+			KeepFiringFor: time.Duration(rule.KeepFiringFor),
+			Labels:        mergeLabels(group.Labels, rule.Labels),
+			Annotations:   rule.Annotations,
+		})
 	}
 	return out
 }
 
+// mergeLabels returns the labels of a rule group merged with the labels of
+// one of its rules, which take precedence. It returns nil if both are empty.
+func mergeLabels(group, rule map[string]string) map[string]string {
+	if len(group) == 0 {
+		return rule
+	}
+	merged := maps.Clone(group)
+	maps.Copy(merged, rule)
+	return merged
+}
+
 // instanceSource loads rule groups from a live Prometheus instance.
 type instanceSource struct {
 	app      *promcheckApp
@@ -347,9 +682,11 @@ func (app *promcheckApp) checkRulesFromPrometheusInstance(ctx context.Context) e
 
 func prometheusv1ToPromcheck(group prometheusv1.RuleGroup) checker.RuleGroup {
 	convertedRuleGroup := checker.RuleGroup{
-		Name:  group.Name,
-		File:  group.File,
-		Rules: []checker.Rule{},
+		Name: group.Name,
+		File: group.File,
+		// the API reports the interval in seconds
+		Interval: time.Duration(group.Interval * float64(time.Second)),
+		Rules:    []checker.Rule{},
 	}
 	for _, rule := range group.Rules {
 		switch v := rule.(type) {
@@ -357,17 +694,36 @@ func prometheusv1ToPromcheck(group prometheusv1.RuleGroup) checker.RuleGroup {
 			convertedRuleGroup.Rules = append(convertedRuleGroup.Rules, checker.Rule{
 				Name:       v.Name,
 				Expression: v.Query,
+				Labels:     labelSetToMap(v.Labels),
 			})
 		case prometheusv1.AlertingRule:
 			convertedRuleGroup.Rules = append(convertedRuleGroup.Rules, checker.Rule{
 				Name:       v.Name,
 				Expression: v.Query,
+				Alerting:   true,
+				// the API reports the for duration in seconds
+				For:         time.Duration(v.Duration * float64(time.Second)),
+				Labels:      labelSetToMap(v.Labels),
+				Annotations: labelSetToMap(v.Annotations),
 			})
 		}
 	}
 	return convertedRuleGroup
 }
 
+// labelSetToMap converts the labels or annotations of a rule returned by the
+// rules API, nil if there are none.
+func labelSetToMap(set model.LabelSet) map[string]string {
+	if len(set) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(set))
+	for name, value := range set {
+		out[string(name)] = string(value)
+	}
+	return out
+}
+
 // inlineSource builds a single synthetic rule group from inline PromQL queries.
 type inlineSource struct {
 	expressions []string
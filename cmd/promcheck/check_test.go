@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"io"
 	"log/slog"
 	"net/http"
@@ -15,10 +16,13 @@ import (
 
 	"github.com/prometheus/client_golang/api"
 	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
 	promql "github.com/prometheus/prometheus/promql/parser"
 	"github.com/stretchr/testify/require"
 
 	"github.com/cbrgm/promcheck/internal/checker"
+	"github.com/cbrgm/promcheck/internal/metrics"
+	"github.com/cbrgm/promcheck/internal/report"
 )
 
 func newTestLogger() *slog.Logger {
@@ -31,17 +35,31 @@ type fakeChecker struct {
 	// ignoredGroups names the groups IsIgnoredGroup reports as ignored.
 	ignoredGroups []string
 
+	// stats is returned by ProbeStats
+	stats checker.ProbeStats
+
 	mu            sync.Mutex
 	checkedGroups []string
+	runs          int
+	runGroups     []checker.RuleGroup
+	hasDeadline   bool
+}
+
+func (f *fakeChecker) StartRun(groups ...checker.RuleGroup) {
+	f.runs++
+	f.runGroups = groups
 }
 
+func (f *fakeChecker) ProbeStats() checker.ProbeStats { return f.stats }
+
 func (f *fakeChecker) IsIgnoredGroup(name string) bool {
 	return slices.Contains(f.ignoredGroups, name)
 }
 
-func (f *fakeChecker) CheckRuleGroup(_ context.Context, group checker.RuleGroup) ([]checker.CheckResult, error) {
+func (f *fakeChecker) CheckRuleGroup(ctx context.Context, group checker.RuleGroup) ([]checker.CheckResult, error) {
 	f.mu.Lock()
 	f.checkedGroups = append(f.checkedGroups, group.Name)
+	_, f.hasDeadline = ctx.Deadline()
 	f.mu.Unlock()
 	return f.res, nil
 }
@@ -49,12 +67,20 @@ func (f *fakeChecker) CheckRuleGroup(_ context.Context, group checker.RuleGroup)
 type fakeReporter struct {
 	sections    int
 	groupsTotal int
+	retries     int
+	fixed       []report.BaselineEntry
 	dumped      bool
 }
 
-func (r *fakeReporter) AddSection(_, _, _, _ string, _, _ []string) { r.sections++ }
-func (r *fakeReporter) AddTotalCheckedGroups(count int)             { r.groupsTotal = count }
-func (r *fakeReporter) Dump() error                                 { r.dumped = true; return nil }
+func (r *fakeReporter) AddSection(_, _, _, _ string, _, _ []string, _ ...report.SectionOption) {
+	r.sections++
+}
+func (r *fakeReporter) AddTotalCheckedGroups(count int) { r.groupsTotal = count }
+func (r *fakeReporter) AddProbeRetries(count int)       { r.retries += count }
+func (r *fakeReporter) Dump() error                     { r.dumped = true; return nil }
+func (r *fakeReporter) AddBaselineFixed(entries ...report.BaselineEntry) {
+	r.fixed = append(r.fixed, entries...)
+}
 
 type staticSource struct{ groups []checker.RuleGroup }
 
@@ -98,6 +124,53 @@ func TestRunCheck_SkipsIgnoredGroups(t *testing.T) {
 	require.Equal(t, 1, rep.sections, "ignored group must not produce sections")
 }
 
+func TestRunCheck_FiltersRules(t *testing.T) {
+	rep := &fakeReporter{}
+	fc := &fakeChecker{res: []checker.CheckResult{{Name: "r", Results: []string{`up`}}}}
+	filter, err := newRuleFilter("alert", []string{"severity=critical"})
+	require.NoError(t, err)
+	app := &promcheckApp{check: fc, report: rep, logger: newTestLogger(), filter: filter}
+	src := staticSource{groups: []checker.RuleGroup{
+		{Name: "alerts", Rules: []checker.Rule{{Name: "a", Expression: "up == 0", Alerting: true, Labels: map[string]string{"severity": "critical"}}}},
+		{Name: "records", Rules: []checker.Rule{{Name: "r", Expression: "sum(up)"}}},
+	}}
+	require.NoError(t, app.runCheck(t.Context(), src))
+	require.Equal(t, []string{"alerts"}, fc.checkedGroups)
+	require.Equal(t, 1, rep.groupsTotal)
+}
+
+func TestRunCheck_FilteringOutAllRulesReturnsErrNoRuleGroups(t *testing.T) {
+	rep := &fakeReporter{}
+	fc := &fakeChecker{}
+	filter, err := newRuleFilter("alert", []string{"severity=critical"})
+	require.NoError(t, err)
+	app := &promcheckApp{check: fc, report: rep, logger: newTestLogger(), filter: filter}
+	src := staticSource{groups: []checker.RuleGroup{
+		{Name: "alerts", Rules: []checker.Rule{{Name: "a", Expression: "up == 0", Alerting: true, Labels: map[string]string{"severity": "warning"}}}},
+		{Name: "records", Rules: []checker.Rule{{Name: "r", Expression: "sum(up)"}}},
+	}}
+	err = app.runCheck(t.Context(), src)
+	require.ErrorIs(t, err, ErrNoRuleGroups)
+	require.Equal(t, exitUsage, exitCodeFor(err))
+	require.Empty(t, fc.checkedGroups)
+	require.False(t, rep.dumped, "nothing matched must not be reported")
+}
+
+func TestRunCheck_StartsRunWithAllLoadedGroups(t *testing.T) {
+	fc := &fakeChecker{
+		res:           []checker.CheckResult{{Name: "r", Results: []string{`up`}}},
+		ignoredGroups: []string{"records"},
+	}
+	app := &promcheckApp{check: fc, report: &fakeReporter{}, logger: newTestLogger()}
+	groups := []checker.RuleGroup{
+		{Name: "records", Rules: []checker.Rule{{Name: "job:up:sum", Expression: "sum by (job) (up)"}}},
+		{Name: "alerts", Rules: []checker.Rule{{Name: "a", Expression: "job:up:sum == 0", Alerting: true}}},
+	}
+	require.NoError(t, app.runCheck(t.Context(), staticSource{groups: slices.Clone(groups)}))
+	require.Equal(t, []string{"alerts"}, fc.checkedGroups)
+	require.Equal(t, groups, fc.runGroups, "recording rules of ignored groups must still be resolvable")
+}
+
 func TestRunCheck_StrictModeDoesNotExitInExporterMode(t *testing.T) {
 	rep := &fakeReporter{}
 	app := &promcheckApp{
@@ -136,6 +209,70 @@ func TestRunCheck_StrictModeReturnsErrStrictFindingsOneShot(t *testing.T) {
 	require.True(t, rep.dumped, "report must still be dumped before returning the sentinel")
 }
 
+func TestRunCheck_StrictPartialResponseReturnsErrStrictFindings(t *testing.T) {
+	fc := &fakeChecker{res: []checker.CheckResult{{
+		Name:    "r",
+		Results: []string{`up`},
+		Selectors: []checker.SelectorResult{
+			{Selector: `up`, Warnings: []string{"store-1 unavailable"}, PartialResponse: true},
+		},
+	}}}
+	app := &promcheckApp{check: fc, report: &fakeReporter{}, logger: newTestLogger(), optStrictMode: true}
+	src := staticSource{groups: []checker.RuleGroup{{Name: "g", Rules: []checker.Rule{{Name: "r", Expression: "up"}}}}}
+
+	require.NoError(t, app.runCheck(t.Context(), src), "partial responses are no strict findings by default")
+
+	app.optStrictPartialResponse = true
+	require.ErrorIs(t, app.runCheck(t.Context(), src), ErrStrictFindings)
+}
+
+func TestRunCheck_RuleErrorsReturnErrRuleErrorsAfterDump(t *testing.T) {
+	rep := &fakeReporter{}
+	app := &promcheckApp{
+		check: &fakeChecker{res: []checker.CheckResult{
+			{Name: "r", NoResults: []string{`up`}},
+			{Name: "broken", Error: errors.New("parse error")},
+		}},
+		report:        rep,
+		logger:        newTestLogger(),
+		optStrictMode: true,
+	}
+	src := staticSource{groups: []checker.RuleGroup{{Name: "g", Rules: []checker.Rule{{Name: "r", Expression: "up"}}}}}
+
+	err := app.runCheck(t.Context(), src)
+	require.ErrorIs(t, err, ErrRuleErrors, "rule errors must take precedence over strict findings")
+	require.True(t, rep.dumped, "report must still be dumped before returning the sentinel")
+	require.Equal(t, 2, rep.sections, "errored rules must be reported as sections")
+
+	rep = &fakeReporter{}
+	app.report = rep
+	app.optExporterModeEnabled = true
+	require.NoError(t, app.runCheck(t.Context(), src), "exporter mode must swallow rule errors")
+	require.True(t, rep.dumped)
+}
+
+func TestRunCheck_UncheckedRulesReturnErrCheckTimeoutAfterDump(t *testing.T) {
+	rep := &fakeReporter{}
+	fc := &fakeChecker{res: []checker.CheckResult{
+		{Name: "r", Results: []string{`up`}},
+		{Name: "slow", Unchecked: true},
+	}}
+	app := &promcheckApp{
+		check:                  fc,
+		report:                 rep,
+		logger:                 newTestLogger(),
+		optCheckTimeout:        time.Minute,
+		optExporterModeEnabled: true,
+	}
+	src := staticSource{groups: []checker.RuleGroup{{Name: "g", Rules: []checker.Rule{{Name: "r", Expression: "up"}}}}}
+
+	err := app.runCheck(t.Context(), src)
+	require.ErrorIs(t, err, ErrCheckTimeout, "an incomplete run must fail even in exporter mode")
+	require.True(t, fc.hasDeadline, "--check.timeout must set a deadline on the run")
+	require.True(t, rep.dumped, "report must list the unchecked rules before returning the sentinel")
+	require.Equal(t, 2, rep.sections)
+}
+
 // TestRunCheck_StrictModeExitsOneShot verifies the full contract end to end:
 // a one-shot --strict run with a dead rule exits the process with status 1.
 // Since os.Exit terminates the process, this is exercised via a subprocess
@@ -199,6 +336,55 @@ func TestProcessFile_ParsesRecordsAndAlerts(t *testing.T) {
 	require.ElementsMatch(t, []string{"HighLatency", "job:up:sum"}, names)
 }
 
+func TestProcessFile_CarriesRuleMetadata(t *testing.T) {
+	p := promql.NewParser(promql.Options{})
+	groups, err := processFile(p, slog.New(slog.NewTextHandler(io.Discard, nil)), "testdata/rules_basic.yaml")
+	require.NoError(t, err)
+	require.Equal(t, 30*time.Second, groups[0].Interval)
+	require.Equal(t, []checker.Rule{
+		{
+			Name:          "HighLatency",
+			Expression:    `up{job="x"} > 0`,
+			Alerting:      true,
+			For:           10 * time.Minute,
+			KeepFiringFor: 5 * time.Minute,
+			Labels:        map[string]string{"team": "infra", "severity": "critical"},
+			Annotations:   map[string]string{"summary": "latency is high"},
+		},
+		{Name: "job:up:sum", Expression: `sum(up{job="x"})`, Labels: map[string]string{"team": "infra"}},
+	}, groups[0].Rules)
+}
+
+func TestPrometheusv1ToPromcheck_CarriesRuleMetadata(t *testing.T) {
+	group := prometheusv1ToPromcheck(prometheusv1.RuleGroup{
+		Name:     "example",
+		File:     "rules.yaml",
+		Interval: 30,
+		Rules: prometheusv1.Rules{
+			prometheusv1.AlertingRule{
+				Name:        "HighLatency",
+				Query:       `up{job="x"} > 0`,
+				Duration:    600,
+				Labels:      model.LabelSet{"severity": "critical"},
+				Annotations: model.LabelSet{"summary": "latency is high"},
+			},
+			prometheusv1.RecordingRule{Name: "job:up:sum", Query: `sum(up{job="x"})`, Labels: model.LabelSet{"team": "infra"}},
+		},
+	})
+	require.Equal(t, 30*time.Second, group.Interval)
+	require.Equal(t, []checker.Rule{
+		{
+			Name:        "HighLatency",
+			Expression:  `up{job="x"} > 0`,
+			Alerting:    true,
+			For:         10 * time.Minute,
+			Labels:      map[string]string{"severity": "critical"},
+			Annotations: map[string]string{"summary": "latency is high"},
+		},
+		{Name: "job:up:sum", Expression: `sum(up{job="x"})`, Labels: map[string]string{"team": "infra"}},
+	}, group.Rules)
+}
+
 func TestProcessFile_AppliesQueryOffset(t *testing.T) {
 	p := promql.NewParser(promql.Options{})
 	groups, err := processFile(p, slog.New(slog.NewTextHandler(io.Discard, nil)), "testdata/rules_query_offset.yaml")
@@ -232,3 +418,192 @@ func TestProcessFile_UTF8Names(t *testing.T) {
 	require.Len(t, groups, 1)
 	require.Equal(t, "SlowRoute", groups[0].Rules[0].Name)
 }
+
+func TestSectionOptions_CarriesDiagnoses(t *testing.T) {
+	cr := checker.CheckResult{
+		NoResults: []string{`up{job="x"}`},
+		Selectors: []checker.SelectorResult{{
+			Selector:  `up{job="x"}`,
+			Diagnosis: &checker.Diagnosis{Culprits: []string{`job="x"`}},
+		}},
+	}
+	var section report.Section
+	for _, opt := range sectionOptions(cr) {
+		opt(&section)
+	}
+	require.Equal(t, []report.SelectorDetail{{
+		Selector:  `up{job="x"}`,
+		Diagnosis: &report.Diagnosis{Culprits: []string{`job="x"`}},
+	}}, section.Selectors)
+}
+
+func TestSectionOptions_CarriesSelectorDetails(t *testing.T) {
+	seen := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	cr := checker.CheckResult{
+		NoResults: []string{`batch_run`, `never_seen`},
+		Selectors: []checker.SelectorResult{
+			{Selector: `batch_run`, LastSeen: seen, Stale: true, Series: 3, SeriesLimit: 2},
+			{Selector: `never_seen`, Stale: true, NotSeenWithin: 2 * time.Hour},
+			{Selector: `up{job=~"a|b"}`, DeadAlternatives: []string{`job="b"`}, Warnings: []string{"store-1 unavailable"}, PartialResponse: true},
+			{Selector: `foo`, Series: 5, MissingLabels: []checker.MissingLabel{{Label: "team", Series: 3, Total: 5}}},
+		},
+	}
+	var section report.Section
+	for _, opt := range sectionOptions(cr) {
+		opt(&section)
+	}
+	require.Equal(t, []report.SelectorDetail{
+		{Selector: `batch_run`, LastSeen: &seen, Stale: true, Series: 3, SeriesLimit: 2},
+		{Selector: `never_seen`, Stale: true, NotSeenWithinSeconds: 7200},
+		{Selector: `up{job=~"a|b"}`, DeadAlternatives: []string{`job="b"`}, Warnings: []string{"store-1 unavailable"}, PartialResponse: true},
+		{Selector: `foo`, Series: 5, MissingLabels: []report.MissingLabel{{Label: "team", Series: 3, Total: 5}}},
+	}, section.Selectors)
+}
+
+func TestSectionOptions_CarriesEmptyJoins(t *testing.T) {
+	cr := checker.CheckResult{
+		Results: []string{`foo`, `bar`},
+		EmptyJoins: []checker.EmptyJoin{{
+			Expression: `foo * on (pod) bar`, Matching: "on (pod)",
+			LHS: 2, RHS: 1, LHSExample: `{pod="a"}`, RHSExample: `{pod="A"}`,
+		}},
+	}
+	var section report.Section
+	for _, opt := range sectionOptions(cr) {
+		opt(&section)
+	}
+	require.Equal(t, []report.EmptyJoin{{
+		Expression: `foo * on (pod) bar`, Matching: "on (pod)",
+		LHS: 2, RHS: 1, LHSExample: `{pod="a"}`, RHSExample: `{pod="A"}`,
+	}}, section.EmptyJoins)
+}
+
+func TestSectionOptions_CarriesRecordingRuleDependencies(t *testing.T) {
+	cr := checker.CheckResult{
+		Results:   []string{`job:foo:rate5m`},
+		NoResults: []string{`job:bar:rate5m`},
+		Selectors: []checker.SelectorResult{
+			{Selector: `job:foo:rate5m`, NotRecorded: true},
+			{Selector: `job:bar:rate5m`, DependencyChains: [][]string{{"job:bar:rate5m", "bar_total[5m]"}}},
+		},
+	}
+	var section report.Section
+	for _, opt := range sectionOptions(cr) {
+		opt(&section)
+	}
+	require.Equal(t, []report.SelectorDetail{
+		{Selector: `job:foo:rate5m`, NotRecorded: true},
+		{Selector: `job:bar:rate5m`, DependencyChains: [][]string{{"job:bar:rate5m", "bar_total[5m]"}}},
+	}, section.Selectors)
+}
+
+func TestSectionOptions_CarriesExpectedEmpty(t *testing.T) {
+	var section report.Section
+	for _, opt := range sectionOptions(checker.CheckResult{Results: []string{`up`}, ExpectedEmpty: []string{`heartbeat`}}) {
+		opt(&section)
+	}
+	require.Equal(t, []string{`heartbeat`}, section.ExpectedEmpty)
+}
+
+func TestSectionOptions_CarriesSuppressed(t *testing.T) {
+	expires := time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
+	cr := checker.CheckResult{Suppressed: []checker.SuppressedSelector{
+		{Selector: `legacy_requests_total`, Expires: expires, Reason: "migration"},
+		{Selector: `batch_runs_total`},
+	}}
+	var section report.Section
+	for _, opt := range sectionOptions(cr) {
+		opt(&section)
+	}
+	require.Equal(t, []report.SuppressedSelector{
+		{Selector: `legacy_requests_total`, Expires: &expires, Reason: "migration"},
+		{Selector: `batch_runs_total`},
+	}, section.Suppressed)
+}
+
+func TestSectionOptions_CarriesRuleStatus(t *testing.T) {
+	var section report.Section
+	for _, opt := range sectionOptions(checker.CheckResult{Results: []string{`up`}, Status: checker.RuleStatusResults, Series: 2}) {
+		opt(&section)
+	}
+	require.Equal(t, report.RuleStatusResults, section.Status)
+	require.Equal(t, 2, section.Series)
+}
+
+func TestSectionOptions_CarriesBacktest(t *testing.T) {
+	cr := checker.CheckResult{Backtest: &checker.Backtest{
+		Range: 24 * time.Hour, Step: time.Minute, Pending: 4, Firing: 2,
+		PendingDuration: 20 * time.Minute, FiringDuration: time.Hour, FiringRatio: 0.04,
+	}}
+	var section report.Section
+	for _, opt := range sectionOptions(cr) {
+		opt(&section)
+	}
+	require.Equal(t, &report.Backtest{
+		RangeSeconds: 86400, StepSeconds: 60, Pending: 4, Firing: 2,
+		PendingSeconds: 1200, FiringSeconds: 3600, FiringRatio: 0.04,
+	}, section.Backtest)
+}
+
+func TestSectionOptions_CarriesRuleMetadata(t *testing.T) {
+	cr := checker.CheckResult{
+		Interval:      30 * time.Second,
+		Alerting:      true,
+		For:           10 * time.Minute,
+		KeepFiringFor: 5 * time.Minute,
+		Labels:        map[string]string{"severity": "critical"},
+		Annotations:   map[string]string{"summary": "latency is high"},
+	}
+	var section report.Section
+	for _, opt := range sectionOptions(cr) {
+		opt(&section)
+	}
+	require.Equal(t, report.Section{
+		Type:                 report.RuleTypeAlert,
+		IntervalSeconds:      30,
+		ForSeconds:           600,
+		KeepFiringForSeconds: 300,
+		Labels:               map[string]string{"severity": "critical"},
+		Annotations:          map[string]string{"summary": "latency is high"},
+	}, section)
+
+	section = report.Section{}
+	for _, opt := range sectionOptions(checker.CheckResult{}) {
+		opt(&section)
+	}
+	require.Equal(t, report.RuleTypeRecord, section.Type)
+}
+
+func TestSectionOptions_CarriesRuleError(t *testing.T) {
+	var section report.Section
+	for _, opt := range sectionOptions(checker.CheckResult{Error: errors.New("bad_data: parse error")}) {
+		opt(&section)
+	}
+	require.Equal(t, "bad_data: parse error", section.Error)
+
+	section = report.Section{}
+	for _, opt := range sectionOptions(checker.CheckResult{Unchecked: true}) {
+		opt(&section)
+	}
+	require.True(t, section.Unchecked)
+}
+
+func TestRunCheck_StartsRunAndRecordsProbeStats(t *testing.T) {
+	fc := &fakeChecker{
+		res:   []checker.CheckResult{{Name: "r", Results: []string{`up`}}},
+		stats: checker.ProbeStats{Hits: 3, Shared: 1, Misses: 2, Retries: map[string]int{"network": 2, "server": 1}},
+	}
+	m := metrics.NewPrometheus(metrics.Options{})
+	rep := &fakeReporter{}
+	app := &promcheckApp{check: fc, report: rep, logger: newTestLogger(), metrics: m}
+	src := staticSource{groups: []checker.RuleGroup{{Name: "g", Rules: []checker.Rule{{Name: "r", Expression: "up"}}}}}
+	require.NoError(t, app.runCheck(t.Context(), src))
+	require.Equal(t, 1, fc.runs)
+
+	rec := httptest.NewRecorder()
+	m.CreateHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Contains(t, rec.Body.String(), `promcheck_probe_cache_lookups_total{result="hit"} 3`)
+	require.Contains(t, rec.Body.String(), `promcheck_probe_cache_lookups_total{result="miss"} 2`)
+	require.Contains(t, rec.Body.String(), `promcheck_probe_retries_total{class="network"} 2`)
+	require.Equal(t, 3, rep.retries, "retries must be added to the report")
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/cbrgm/promcheck/internal/report"
+)
+
+// diffCmd represents the diff command, comparing the json reports of two runs.
+type diffCmd struct {
+	Old    string `arg:"" name:"old" help:"The json report of the earlier run, e.g. yesterday's or staging's"`
+	New    string `arg:"" name:"new" help:"The json report of the later run, e.g. today's or production's"`
+	Format string `name:"diff.format" enum:"graph,json,markdown" default:"graph" help:"The output format of the diff: graph, json or markdown"`
+}
+
+// runDiff prints the diff between the reports of cmd to w, returning the
+// process exit code: exitFindings if any selector is newly failing.
+func runDiff(cmd diffCmd, w io.Writer, useColor bool, logger *slog.Logger) int {
+	oldReport, err := readReport(cmd.Old)
+	if err != nil {
+		logger.Error("failed to read report", "err", err)
+		return exitUsage
+	}
+	newReport, err := readReport(cmd.New)
+	if err != nil {
+		logger.Error("failed to read report", "err", err)
+		return exitUsage
+	}
+
+	diff := report.NewDiff(oldReport, newReport)
+	switch cmd.Format {
+	case report.JSONFormat:
+		raw, err := diff.ToJSON()
+		if err != nil {
+			logger.Error("failed to print diff", "err", err)
+			return exitRuntime
+		}
+		_, _ = fmt.Fprintln(w, raw)
+	case report.MarkdownFormat:
+		_, _ = fmt.Fprint(w, diff.ToMarkdown())
+	default:
+		_, _ = fmt.Fprintln(w, diff.ToTree(useColor))
+	}
+
+	if diff.HasRegressions() {
+		return exitFindings
+	}
+	return exitOK
+}
+
+// readReport reads the json report at path.
+func readReport(path string) (report.Report, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return report.Report{}, err
+	}
+	defer f.Close()
+	r, err := report.ReadJSON(f)
+	if err != nil {
+		return report.Report{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return r, nil
+}
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cbrgm/promcheck/internal/report"
+)
+
+// writeTestReport writes the json report of a single rule with the given selectors and returns its path.
+func writeTestReport(t *testing.T, failed, success []string) string {
+	t.Helper()
+	b := report.NewBuilder(report.WithWriter(io.Discard), report.WithoutColor())
+	b.AddSection("f.yaml", "g", "r", `foo or bar`, failed, success)
+	raw, err := b.ToJSON()
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "report.json")
+	require.NoError(t, os.WriteFile(path, []byte(raw), 0o600))
+	return path
+}
+
+func TestRunDiff_ExitCodes(t *testing.T) {
+	healthy := writeTestReport(t, nil, []string{`foo`, `bar`})
+	broken := writeTestReport(t, []string{`foo`}, []string{`bar`})
+
+	var out bytes.Buffer
+	require.Equal(t, exitFindings, runDiff(diffCmd{Old: healthy, New: broken}, &out, false, newTestLogger()))
+	require.Contains(t, out.String(), "Newly failing selectors: 1\n└── [✖] f.yaml > g > r: foo")
+
+	out.Reset()
+	require.Equal(t, exitOK, runDiff(diffCmd{Old: broken, New: healthy, Format: "markdown"}, &out, false, newTestLogger()))
+	require.Contains(t, out.String(), "### Recovered selectors (1)")
+
+	out.Reset()
+	require.Equal(t, exitOK, runDiff(diffCmd{Old: broken, New: broken, Format: "json"}, &out, false, newTestLogger()))
+	require.Contains(t, out.String(), `"newly_failing": []`)
+}
+
+func TestRunDiff_UnreadableReportIsUsageError(t *testing.T) {
+	healthy := writeTestReport(t, nil, []string{`foo`})
+	invalid := filepath.Join(t.TempDir(), "invalid.json")
+	require.NoError(t, os.WriteFile(invalid, []byte("not json"), 0o600))
+
+	require.Equal(t, exitUsage, runDiff(diffCmd{Old: healthy, New: invalid}, io.Discard, false, newTestLogger()))
+	require.Equal(t, exitUsage, runDiff(diffCmd{Old: filepath.Join(t.TempDir(), "missing.json"), New: healthy}, io.Discard, false, newTestLogger()))
+}
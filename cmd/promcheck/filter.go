@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/cbrgm/promcheck/internal/checker"
+	"github.com/cbrgm/promcheck/internal/report"
+)
+
+// ruleFilter selects the rules to check by type and labels. Unlike
+// --check.match, it applies to rules of every source.
+type ruleFilter struct {
+	// ruleType represents the type of the rules to check, see report.RuleType*
+	// constants, empty or "all" for rules of any type
+	ruleType string
+
+	// labels represents the labels a rule must carry, all of them
+	labels map[string]string
+}
+
+// newRuleFilter returns a ruleFilter for the given rule type and labels,
+// given as <name>=<value>.
+func newRuleFilter(ruleType string, labels []string) (ruleFilter, error) {
+	f := ruleFilter{ruleType: ruleType, labels: make(map[string]string, len(labels))}
+	for _, l := range labels {
+		name, value, ok := strings.Cut(l, "=")
+		if !ok || name == "" {
+			return ruleFilter{}, fmt.Errorf("invalid rule label %q, want <name>=<value>", l)
+		}
+		if _, dup := f.labels[name]; dup {
+			return ruleFilter{}, fmt.Errorf("rule label %q given more than once", name)
+		}
+		f.labels[name] = value
+	}
+	return f, nil
+}
+
+// matches reports whether the given rule passes the filter.
+func (f ruleFilter) matches(rule checker.Rule) bool {
+	switch f.ruleType {
+	case report.RuleTypeAlert:
+		if !rule.Alerting {
+			return false
+		}
+	case report.RuleTypeRecord:
+		if rule.Alerting {
+			return false
+		}
+	}
+	for name, value := range f.labels {
+		if got, ok := rule.Labels[name]; !ok || got != value {
+			return false
+		}
+	}
+	return true
+}
+
+// apply returns the given groups with the rules not passing the filter
+// removed, leaving out groups without any rule left.
+func (f ruleFilter) apply(groups []checker.RuleGroup) []checker.RuleGroup {
+	filtered := make([]checker.RuleGroup, 0, len(groups))
+	for _, group := range groups {
+		rules := make([]checker.Rule, 0, len(group.Rules))
+		for _, rule := range group.Rules {
+			if f.matches(rule) {
+				rules = append(rules, rule)
+			}
+		}
+		if len(rules) == 0 {
+			continue
+		}
+		group.Rules = rules
+		filtered = append(filtered, group)
+	}
+	return filtered
+}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/cbrgm/promcheck/internal/checker"
+)
+
+func TestNewRuleFilter_RejectsInvalidLabels(t *testing.T) {
+	_, err := newRuleFilter("all", []string{"severity"})
+	require.Error(t, err)
+	_, err = newRuleFilter("all", []string{"=critical"})
+	require.Error(t, err)
+	_, err = newRuleFilter("all", []string{"severity=critical", "severity=warning"})
+	require.Error(t, err)
+
+	f, err := newRuleFilter("all", []string{"severity=critical", "team="})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"severity": "critical", "team": ""}, f.labels)
+}
+
+func TestRuleFilter_Apply(t *testing.T) {
+	alert := checker.Rule{Name: "HighLatency", Alerting: true, Labels: map[string]string{"severity": "critical", "team": "infra"}}
+	warning := checker.Rule{Name: "SlowLatency", Alerting: true, Labels: map[string]string{"severity": "warning"}}
+	record := checker.Rule{Name: "job:up:sum", Labels: map[string]string{"team": "infra"}}
+	inline := checker.Rule{Name: "query-0"}
+	groups := []checker.RuleGroup{
+		{Name: "alerts", Rules: []checker.Rule{alert, warning}},
+		{Name: "records", Rules: []checker.Rule{record}},
+		{Name: "[inline]", Rules: []checker.Rule{inline}},
+	}
+
+	tests := []struct {
+		name     string
+		ruleType string
+		labels   []string
+		want     []checker.RuleGroup
+	}{
+		{"all", "all", nil, groups},
+		{"zero value", "", nil, groups},
+		{"alerts", "alert", nil, groups[:1]},
+		{
+			"records", "record", nil,
+			[]checker.RuleGroup{groups[1], groups[2]},
+		},
+		{
+			"label", "all", []string{"severity=critical"},
+			[]checker.RuleGroup{{Name: "alerts", Rules: []checker.Rule{alert}}},
+		},
+		{
+			"type and label", "record", []string{"team=infra"},
+			[]checker.RuleGroup{groups[1]},
+		},
+		{"all labels must match", "all", []string{"team=infra", "severity=warning"}, []checker.RuleGroup{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := newRuleFilter(tt.ruleType, tt.labels)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, f.apply(groups))
+		})
+	}
+}
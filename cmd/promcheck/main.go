@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"github.com/alecthomas/kong"
+
+	"github.com/cbrgm/promcheck/internal/report"
 )
 
 const (
@@ -23,7 +25,10 @@ const (
 const (
 	// exitOK means the run completed with no findings (or wasn't strict).
 	exitOK = 0
-	// exitFindings means --strict was set and one or more selectors had no results.
+	// exitFindings means --strict was set and one or more selectors had no
+	// results (not recorded in the --baseline, if given), or were probed from
+	// a partial response with --strict.partial-response. For the diff
+	// command, it means one or more selectors are newly failing.
 	exitFindings = 1
 	// exitUsage means a usage or configuration error: bad flags, a bad
 	// regexp, or nothing matched to check.
@@ -31,6 +36,12 @@ const (
 	// exitRuntime means a runtime failure while probing: connection,
 	// query, or parse error.
 	exitRuntime = 3
+	// exitRuleErrors means --check.continue-on-error was set and one or more
+	// rules could not be checked because of a query or parse error.
+	exitRuleErrors = 4
+	// exitTimeout means --check.timeout was exceeded before all rules were
+	// checked. It takes precedence over rule errors and findings.
+	exitTimeout = 5
 )
 
 var (
@@ -51,18 +62,48 @@ type config struct {
 	PrometheusBasicAuthPassword string `name:"prometheus.basic-auth-pass" default:"" help:"Basic auth password"`
 
 	// check parameters
-	CheckIgnoredSelectorsRegexp []string `name:"check.ignore-selector" help:"Regexp of selectors to ignore"`
-	CheckIgnoredGroupsRegexp    []string `name:"check.ignore-group" help:"Regexp of rule groups to ignore"`
-	CheckConcurrency            int      `name:"check.concurrency" default:"8" help:"Maximum number of selectors probed in parallel"`
-	CheckFiles                  string   `name:"check.file" help:"The rule files to check."`
-	CheckExpressions            []string `name:"check.query" help:"Inline PromQL expression to check"`
-	CheckMatch                  []string `name:"check.match" help:"PromQL label matchers to filter rules server-side, e.g. '{team=\"infra\"}'"`
+	CheckIgnoredSelectorsRegexp []string      `name:"check.ignore-selector" help:"Regexp of selectors to ignore"`
+	CheckIgnoredGroupsRegexp    []string      `name:"check.ignore-group" help:"Regexp of rule groups to ignore"`
+	CheckConcurrency            int           `name:"check.concurrency" default:"8" help:"Maximum number of selectors probed in parallel"`
+	CheckQPS                    float64       `name:"check.qps" default:"0" help:"Maximum number of queries per second sent to Prometheus across all probes (0 is unlimited)"`
+	CheckBurst                  int           `name:"check.burst" default:"10" help:"Number of queries which may exceed --check.qps at once"`
+	CheckQPSAdaptive            bool          `name:"check.qps-adaptive" default:"false" help:"Halve the query rate whenever Prometheus responds slowly or with 429/503, and restore it as responses are fast again"`
+	CheckProber                 string        `name:"check.prober" enum:"query,series" default:"query" help:"How to probe selectors: count() instant queries (query) or the series API (series)"`
+	CheckBatchSize              int           `name:"check.batch-size" default:"0" help:"Probe up to this many selectors with a single combined query (0 probes every selector with its own query)"`
+	CheckDrillDown              bool          `name:"check.drill-down" default:"false" help:"Re-probe selectors without results with matchers removed to find the culprit matcher"`
+	CheckSuggest                bool          `name:"check.suggest" default:"false" help:"Suggest similar metric names and label values for selectors without results, requires --check.drill-down"`
+	CheckAlternations           bool          `name:"check.alternations" default:"false" help:"Probe every alternative of regex matchers like job=~\"a|b\" of selectors with results on its own to find dead alternatives"`
+	CheckGroupingLabels         bool          `name:"check.grouping-labels" default:"false" help:"Check that the series of selectors with results carry the labels of by, on and group_left/group_right clauses"`
+	CheckEvaluate               bool          `name:"check.evaluate" default:"false" help:"Evaluate the whole expression of every rule and report whether it yields series, and how many"`
+	CheckBacktest               time.Duration `name:"check.backtest" default:"0s" help:"Range of historical data to backtest alerting rules over, e.g. 168h, reporting how often and how long they would have been pending and firing (0 disables)"`
+	CheckBacktestStep           time.Duration `name:"check.backtest-step" default:"1m" help:"Resolution of alert backtests, widened if the range would take more than 10,000 steps"`
+	CheckJoins                  bool          `name:"check.joins" default:"false" help:"Evaluate both operands of binary operations with vector matching to find joins which never match"`
+	CheckRecordingRules         bool          `name:"check.recording-rules" default:"true" help:"Check the source selectors of recording rules loaded in the same run instead of the metric they record if it has no result value, e.g. because the rule isn't deployed yet"`
+	CheckLookback               time.Duration `name:"check.lookback" default:"0s" help:"Window to probe selectors without results over again, e.g. 24h, to tolerate intermittent metrics (0 disables)"`
+	CheckMaxSeries              int           `name:"check.max-series" default:"0" help:"Warn about selectors yielding more series than this (0 disables)"`
+	CheckMaxSeriesSelector      []string      `name:"check.max-series-selector" sep:"none" help:"Per-selector series limit as <selector regexp>=<limit>, taking precedence over --check.max-series"`
+	CheckQueryTimeout           time.Duration `name:"check.query-timeout" default:"0s" help:"Evaluation timeout passed to Prometheus with every query (0 uses the Prometheus default)"`
+	CheckSeriesLimit            uint64        `name:"check.series-limit" default:"0" help:"Maximum number of series returned per probe of the series prober, capping its series counts (0 is unlimited)"`
+	CheckTimeout                time.Duration `name:"check.timeout" default:"0s" help:"Deadline for a whole check run, rules not checked by then are reported as unchecked (0 disables)"`
+	CheckRetries                int           `name:"check.retries" default:"0" help:"Retry queries failing with a transient network or Prometheus error this many times (0 disables)"`
+	CheckRetryBackoff           time.Duration `name:"check.retry-backoff" default:"500ms" help:"Delay before the first retry, doubled on every further retry and randomized by up to half of it"`
+	CheckContinueOnError        bool          `name:"check.continue-on-error" default:"false" help:"Report query and parse errors per rule and keep checking the remaining rules instead of aborting the run"`
+	CheckMaxAge                 time.Duration `name:"check.max-age" default:"0s" help:"Flag selectors whose newest sample is older than this freshness threshold as stale, e.g. 1h (0 disables)"`
+	CheckFiles                  string        `name:"check.file" help:"The rule files to check."`
+	CheckExpressions            []string      `name:"check.query" help:"Inline PromQL expression to check"`
+	CheckRuleType               string        `name:"check.rule-type" enum:"all,alert,record" default:"all" help:"Only check rules of this type: all, alert or record (inline queries count as recording rules)"`
+	CheckRuleLabel              []string      `name:"check.rule-label" sep:"none" help:"Only check rules carrying this label as <name>=<value>, e.g. severity=critical (repeatable, all must match)"`
+	CheckMatch                  []string      `name:"check.match" help:"PromQL label matchers to filter rules server-side, e.g. '{team=\"infra\"}'"`
 
 	// output parameters
 	OutputFormat      string `name:"output.format" enum:"graph,json,yaml" default:"graph" help:"The output format to use"`
 	OutputNoColor     bool   `name:"output.no-color" default:"false" help:"Toggle colored output"`
 	OutputOnlyFailing bool   `name:"output.only-failing" default:"false" help:"Only show rules that have selectors without results"`
 
+	// baseline parameters
+	Baseline      string `name:"baseline" default:"" help:"Baseline file of known findings, --strict only fails on findings not recorded in it"`
+	BaselineWrite string `name:"baseline.write" default:"" help:"Record the findings of the run in this baseline file"`
+
 	// exporter parameters
 	ExporterModeEnabled          bool   `name:"exporter.enabled" default:"false" help:"Run promcheck as a prometheus exporter"`
 	ExporterHTTPAddr             string `name:"exporter.addr" default:"0.0.0.0:9093" help:"The address the http server is running at"`
@@ -76,12 +117,17 @@ type config struct {
 	LogLevel string `name:"log.level" default:"info" enum:"error,warn,info,debug" help:"The log level to use for filtering logs"`
 
 	// etc
-	StrictMode bool `name:"strict" default:"false" help:"Tell promcheck to exit with an error code on expressions without results"`
+	StrictMode            bool `name:"strict" default:"false" help:"Tell promcheck to exit with an error code on expressions without results"`
+	StrictPartialResponse bool `name:"strict.partial-response" default:"false" help:"With --strict, also exit with an error code on selectors probed from a partial response"`
+
+	// commands
+	Check struct{} `cmd:"" default:"1" help:"Check rules for selectors without results (default)"`
+	Diff  diffCmd  `cmd:"" help:"Compare the json reports of two runs and exit with an error code on newly failing selectors"`
 }
 
 func main() {
 	cfg := config{}
-	_ = kong.Parse(&cfg,
+	kctx := kong.Parse(&cfg,
 		kong.Name("promcheck"),
 		kong.Description(
 			fmt.Sprintf(
@@ -106,6 +152,10 @@ func main() {
 
 	logger := newLogger(cfg.LogJSON, cfg.LogLevel)
 
+	if kctx.Selected() != nil && kctx.Selected().Name == "diff" {
+		useColor := report.IsTTY(os.Stdout) && os.Getenv("NO_COLOR") == "" && !cfg.OutputNoColor
+		os.Exit(runDiff(cfg.Diff, os.Stdout, useColor, logger))
+	}
 	os.Exit(runMain(&cfg, logger))
 }
 
@@ -113,14 +163,8 @@ func main() {
 // It exists separately from main so the exit-code contract can be exercised
 // without an os.Exit call terminating the test process.
 func runMain(cfg *config, logger *slog.Logger) int {
-	// validation
-	if cfg.ExporterInterval < 0 {
-		logger.Error("configuration error", "err", "--exporter.interval must be > 0")
-		return exitUsage
-	}
-
-	if cfg.CheckConcurrency < 1 {
-		logger.Error("configuration error", "err", "--check.concurrency must be >= 1")
+	if err := cfg.validate(); err != nil {
+		logger.Error("configuration error", "err", err)
 		return exitUsage
 	}
 
@@ -138,6 +182,46 @@ func runMain(cfg *config, logger *slog.Logger) int {
 	return code
 }
 
+// validate returns a usage error if the flags of cfg are out of range or
+// contradict each other, or the files they refer to don't exist.
+func (cfg *config) validate() error {
+	for _, check := range []struct {
+		invalid bool
+		msg     string
+	}{
+		{cfg.ExporterInterval < 0, "--exporter.interval must be > 0"},
+		{cfg.CheckLookback < 0, "--check.lookback must be >= 0"},
+		{cfg.CheckBacktest < 0, "--check.backtest must be >= 0"},
+		{cfg.CheckBacktest > 0 && cfg.CheckBacktestStep <= 0, "--check.backtest-step must be > 0"},
+		{cfg.CheckMaxAge < 0, "--check.max-age must be >= 0"},
+		{cfg.CheckMaxSeries < 0, "--check.max-series must be >= 0"},
+		{cfg.CheckBatchSize < 0, "--check.batch-size must be >= 0"},
+		{cfg.CheckQPS < 0, "--check.qps must be >= 0"},
+		{cfg.CheckBurst < 1, "--check.burst must be >= 1"},
+		{cfg.CheckQueryTimeout < 0, "--check.query-timeout must be >= 0"},
+		{cfg.CheckTimeout < 0, "--check.timeout must be >= 0"},
+		{cfg.CheckRetries < 0, "--check.retries must be >= 0"},
+		{cfg.CheckRetryBackoff < 0, "--check.retry-backoff must be >= 0"},
+		{cfg.CheckProber == "series" && cfg.CheckBatchSize > 1, "--check.batch-size requires --check.prober=query"},
+		{cfg.StrictPartialResponse && !cfg.StrictMode, "--strict.partial-response requires --strict"},
+		{cfg.CheckSuggest && !cfg.CheckDrillDown, "--check.suggest requires --check.drill-down"},
+		{cfg.CheckConcurrency < 1, "--check.concurrency must be >= 1"},
+	} {
+		if check.invalid {
+			return errors.New(check.msg)
+		}
+	}
+	if _, err := newRuleFilter(cfg.CheckRuleType, cfg.CheckRuleLabel); err != nil {
+		return err
+	}
+	if cfg.Baseline != "" {
+		if _, err := os.Stat(cfg.Baseline); err != nil {
+			return fmt.Errorf("--baseline: %w", err)
+		}
+	}
+	return nil
+}
+
 // exitCodeFor maps a promcheckApp.run error to the documented exit-code
 // contract (see the exit* constants above).
 func exitCodeFor(err error) int {
@@ -146,6 +230,10 @@ func exitCodeFor(err error) int {
 		return exitOK
 	case errors.Is(err, ErrStrictFindings):
 		return exitFindings
+	case errors.Is(err, ErrRuleErrors):
+		return exitRuleErrors
+	case errors.Is(err, ErrCheckTimeout):
+		return exitTimeout
 	case errors.Is(err, ErrNoRuleGroups):
 		return exitUsage
 	default:
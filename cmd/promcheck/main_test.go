@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/exec"
 	"testing"
+	"time"
 
 	"github.com/alecthomas/kong"
 	"github.com/stretchr/testify/require"
@@ -93,6 +94,9 @@ func TestExitCodeFor(t *testing.T) {
 		{"strict findings", ErrStrictFindings, exitFindings},
 		{"wrapped strict findings", fmt.Errorf("check: %w", ErrStrictFindings), exitFindings},
 		{"no rule groups is a usage error", ErrNoRuleGroups, exitUsage},
+		{"rule errors", ErrRuleErrors, exitRuleErrors},
+		{"check timeout", ErrCheckTimeout, exitTimeout},
+		{"wrapped check timeout", fmt.Errorf("check: %w", ErrCheckTimeout), exitTimeout},
 		{"generic error is a runtime failure", errors.New("boom"), exitRuntime},
 	}
 	for _, tt := range tests {
@@ -101,3 +105,217 @@ func TestExitCodeFor(t *testing.T) {
 		})
 	}
 }
+
+func TestConfig_DrillDownAndSuggestDefaultOff(t *testing.T) {
+	var cfg config
+	parser, err := kong.New(&cfg, kong.Name("promcheck"))
+	require.NoError(t, err)
+	_, err = parser.Parse(nil)
+	require.NoError(t, err)
+	require.False(t, cfg.CheckDrillDown, "the drill-down must be opt-in")
+	require.False(t, cfg.CheckSuggest, "suggestions must be opt-in")
+
+	_, err = parser.Parse([]string{"--check.drill-down", "--check.suggest"})
+	require.NoError(t, err)
+	require.True(t, cfg.CheckDrillDown)
+	require.True(t, cfg.CheckSuggest)
+}
+
+func TestConfig_RetriesDefaultOff(t *testing.T) {
+	var cfg config
+	parser, err := kong.New(&cfg, kong.Name("promcheck"))
+	require.NoError(t, err)
+	_, err = parser.Parse(nil)
+	require.NoError(t, err)
+	require.Zero(t, cfg.CheckRetries, "failing queries must not be retried by default")
+
+	_, err = parser.Parse([]string{"--check.retries=2"})
+	require.NoError(t, err)
+	require.Equal(t, 2, cfg.CheckRetries)
+}
+
+func TestConfig_AlternationsGroupingLabelsAndJoinsDefaultOff(t *testing.T) {
+	var cfg config
+	parser, err := kong.New(&cfg, kong.Name("promcheck"))
+	require.NoError(t, err)
+	_, err = parser.Parse(nil)
+	require.NoError(t, err)
+	require.False(t, cfg.CheckAlternations)
+	require.False(t, cfg.CheckGroupingLabels)
+	require.False(t, cfg.CheckJoins)
+
+	_, err = parser.Parse([]string{"--check.alternations", "--check.grouping-labels", "--check.joins"})
+	require.NoError(t, err)
+	require.True(t, cfg.CheckAlternations)
+	require.True(t, cfg.CheckGroupingLabels)
+	require.True(t, cfg.CheckJoins)
+}
+
+func TestConfig_EvaluateDefaultsOff(t *testing.T) {
+	var cfg config
+	parser, err := kong.New(&cfg, kong.Name("promcheck"))
+	require.NoError(t, err)
+	_, err = parser.Parse(nil)
+	require.NoError(t, err)
+	require.False(t, cfg.CheckEvaluate, "whole-expression evaluation must be opt-in")
+
+	_, err = parser.Parse([]string{"--check.evaluate"})
+	require.NoError(t, err)
+	require.True(t, cfg.CheckEvaluate)
+}
+
+func TestConfig_BacktestDefaultsOff(t *testing.T) {
+	var cfg config
+	parser, err := kong.New(&cfg, kong.Name("promcheck"))
+	require.NoError(t, err)
+	_, err = parser.Parse(nil)
+	require.NoError(t, err)
+	require.Zero(t, cfg.CheckBacktest, "backtesting must be off by default")
+	require.Equal(t, time.Minute, cfg.CheckBacktestStep)
+
+	_, err = parser.Parse([]string{"--check.backtest=168h", "--check.backtest-step=5m"})
+	require.NoError(t, err)
+	require.Equal(t, 168*time.Hour, cfg.CheckBacktest)
+	require.Equal(t, 5*time.Minute, cfg.CheckBacktestStep)
+}
+
+func TestConfig_RuleFilters(t *testing.T) {
+	var cfg config
+	parser, err := kong.New(&cfg, kong.Name("promcheck"))
+	require.NoError(t, err)
+	_, err = parser.Parse(nil)
+	require.NoError(t, err)
+	require.Equal(t, "all", cfg.CheckRuleType)
+	require.Empty(t, cfg.CheckRuleLabel)
+
+	_, err = parser.Parse([]string{"--check.rule-type=alert", "--check.rule-label=severity=critical", "--check.rule-label=team=a,b"})
+	require.NoError(t, err)
+	require.Equal(t, "alert", cfg.CheckRuleType)
+	require.Equal(t, []string{"severity=critical", "team=a,b"}, cfg.CheckRuleLabel)
+
+	_, err = parser.Parse([]string{"--check.rule-type=rule"})
+	require.Error(t, err)
+}
+
+func TestConfig_LookbackParsesDuration(t *testing.T) {
+	var cfg config
+	parser, err := kong.New(&cfg, kong.Name("promcheck"))
+	require.NoError(t, err)
+	_, err = parser.Parse(nil)
+	require.NoError(t, err)
+	require.Zero(t, cfg.CheckLookback, "lookback must be off by default")
+
+	_, err = parser.Parse([]string{"--check.lookback", "24h"})
+	require.NoError(t, err)
+	require.Equal(t, 24*time.Hour, cfg.CheckLookback)
+}
+
+func TestConfig_MaxSeriesSelectorKeepsCommas(t *testing.T) {
+	var cfg config
+	parser, err := kong.New(&cfg, kong.Name("promcheck"))
+	require.NoError(t, err)
+	_, err = parser.Parse([]string{
+		"--check.max-series", "1000",
+		"--check.max-series-selector", `kube_pod_info{namespace="a",job="b"}=5000`,
+		"--check.max-series-selector", `up=10`,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1000, cfg.CheckMaxSeries)
+	require.Equal(t, []string{`kube_pod_info{namespace="a",job="b"}=5000`, `up=10`}, cfg.CheckMaxSeriesSelector)
+}
+
+func TestConfig_ProberDefaultsToQuery(t *testing.T) {
+	var cfg config
+	parser, err := kong.New(&cfg, kong.Name("promcheck"))
+	require.NoError(t, err)
+	_, err = parser.Parse(nil)
+	require.NoError(t, err)
+	require.Equal(t, "query", cfg.CheckProber)
+
+	_, err = parser.Parse([]string{"--check.prober", "series"})
+	require.NoError(t, err)
+	require.Equal(t, "series", cfg.CheckProber)
+
+	_, err = parser.Parse([]string{"--check.prober", "carrier-pigeon"})
+	require.Error(t, err)
+}
+
+func TestConfig_QPSDefaultsToUnlimited(t *testing.T) {
+	var cfg config
+	parser, err := kong.New(&cfg, kong.Name("promcheck"))
+	require.NoError(t, err)
+	_, err = parser.Parse(nil)
+	require.NoError(t, err)
+	require.Zero(t, cfg.CheckQPS, "rate limiting must be opt-in")
+	require.Equal(t, 10, cfg.CheckBurst)
+	require.False(t, cfg.CheckQPSAdaptive)
+
+	_, err = parser.Parse([]string{"--check.qps", "2.5", "--check.qps-adaptive"})
+	require.NoError(t, err)
+	require.Equal(t, 2.5, cfg.CheckQPS)
+	require.True(t, cfg.CheckQPSAdaptive)
+}
+
+func TestRunMain_StrictPartialResponseRequiresStrict(t *testing.T) {
+	cfg := &config{CheckBurst: 10, CheckConcurrency: 1, StrictPartialResponse: true}
+	require.Equal(t, exitUsage, runMain(cfg, newTestLogger()))
+}
+
+func TestRunMain_InvalidRuleLabelIsUsageError(t *testing.T) {
+	cfg := &config{CheckBurst: 10, CheckConcurrency: 1, CheckRuleType: "all", CheckRuleLabel: []string{"severity"}}
+	require.Equal(t, exitUsage, runMain(cfg, newTestLogger()))
+}
+
+func TestRunMain_MissingBaselineIsUsageError(t *testing.T) {
+	cfg := &config{CheckBurst: 10, CheckConcurrency: 1, CheckRuleType: "all", Baseline: "testdata/does-not-exist.json"}
+	require.Equal(t, exitUsage, runMain(cfg, newTestLogger()))
+}
+
+func TestConfig_Validate(t *testing.T) {
+	valid := func() config {
+		return config{CheckBurst: 10, CheckConcurrency: 1, CheckRuleType: "all", CheckBacktestStep: time.Minute}
+	}
+	tests := []struct {
+		name    string
+		modify  func(*config)
+		wantErr string
+	}{
+		{"valid", func(*config) {}, ""},
+		{"negative lookback", func(c *config) { c.CheckLookback = -time.Second }, "--check.lookback must be >= 0"},
+		{"backtest without step", func(c *config) { c.CheckBacktest = time.Hour; c.CheckBacktestStep = 0 }, "--check.backtest-step must be > 0"},
+		{"partial response without strict", func(c *config) { c.StrictPartialResponse = true }, "--strict.partial-response requires --strict"},
+		{"suggest without drill-down", func(c *config) { c.CheckSuggest = true }, "--check.suggest requires --check.drill-down"},
+		{"invalid rule label", func(c *config) { c.CheckRuleLabel = []string{"severity"} }, "severity"},
+		{"missing baseline", func(c *config) { c.Baseline = "testdata/does-not-exist.json" }, "--baseline"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid()
+			tt.modify(&cfg)
+			err := cfg.validate()
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestConfig_DiffCommand(t *testing.T) {
+	var cfg config
+	parser, err := kong.New(&cfg, kong.Name("promcheck"))
+	require.NoError(t, err)
+
+	ctx, err := parser.Parse(nil)
+	require.NoError(t, err)
+	require.Equal(t, "check", ctx.Selected().Name, "checking rules must stay the default command")
+
+	ctx, err = parser.Parse([]string{"diff", "old.json", "new.json", "--diff.format=markdown"})
+	require.NoError(t, err)
+	require.Equal(t, "diff", ctx.Selected().Name)
+	require.Equal(t, diffCmd{Old: "old.json", New: "new.json", Format: "markdown"}, cfg.Diff)
+
+	_, err = parser.Parse([]string{"diff", "old.json"})
+	require.Error(t, err, "diff requires two reports")
+}
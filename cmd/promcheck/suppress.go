@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/cbrgm/promcheck/internal/checker"
+)
+
+// suppressionComment represents the prefix of rule file comments suppressing
+// findings of the rule below them, followed by a directive as parsed by
+// checker.ParseSuppression.
+const suppressionComment = "promcheck:ignore"
+
+// fileSuppressions represents the suppressions of the comments above the
+// rules of a rule file, indexed by group and rule.
+type fileSuppressions [][][]checker.Suppression
+
+// rule returns the suppressions of the given rule of the given group.
+func (f fileSuppressions) rule(group, rule int) []checker.Suppression {
+	if group >= len(f) || rule >= len(f[group]) {
+		return nil
+	}
+	return f[group][rule]
+}
+
+// parseFileSuppressions returns the suppressions of the promcheck:ignore
+// comments above the rules of the given rule file content.
+func parseFileSuppressions(content []byte) (fileSuppressions, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	groups := mappingValue(doc.Content[0], "groups")
+	if groups == nil || groups.Kind != yaml.SequenceNode {
+		return nil, nil
+	}
+	out := make(fileSuppressions, len(groups.Content))
+	for i, group := range groups.Content {
+		rules := mappingValue(group, "rules")
+		if rules == nil || rules.Kind != yaml.SequenceNode {
+			continue
+		}
+		out[i] = make([][]checker.Suppression, len(rules.Content))
+		for j, rule := range rules.Content {
+			suppressions, err := parseSuppressionComments(rule.HeadComment)
+			if err != nil {
+				return nil, fmt.Errorf("rule at line %d: %w", rule.Line, err)
+			}
+			out[i][j] = suppressions
+		}
+	}
+	return out, nil
+}
+
+// parseSuppressionComments returns the suppressions of the promcheck:ignore
+// lines of the given comment, ignoring any other lines.
+func parseSuppressionComments(comment string) ([]checker.Suppression, error) {
+	var out []checker.Suppression
+	for line := range strings.Lines(comment) {
+		line = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(line), "#"))
+		directive, ok := strings.CutPrefix(line, suppressionComment)
+		if !ok || (directive != "" && directive[0] != ' ' && directive[0] != '\t') {
+			continue
+		}
+		s, err := checker.ParseSuppression(directive)
+		if err != nil {
+			return nil, fmt.Errorf("%s comment: %w", suppressionComment, err)
+		}
+		out = append(out, s)
+	}
+	return out, nil
+}
+
+// mappingValue returns the value of the given key of a mapping node, nil if
+// node is no mapping or lacks the key.
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
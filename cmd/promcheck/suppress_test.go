@@ -0,0 +1,43 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	promql "github.com/prometheus/prometheus/promql/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessFile_CarriesSuppressionComments(t *testing.T) {
+	p := promql.NewParser(promql.Options{})
+	groups, err := processFile(p, slog.New(slog.NewTextHandler(io.Discard, nil)), "testdata/rules_suppressed.yaml")
+	require.NoError(t, err)
+	require.Len(t, groups, 2)
+
+	latency := groups[0].Rules[0].Suppressions
+	require.Len(t, latency, 1)
+	require.Equal(t, "http_request_duration_.*", latency[0].Selector.String())
+	require.Equal(t, time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC), latency[0].Expires)
+	require.Equal(t, "API moves to the new exporter", latency[0].Reason)
+
+	require.Empty(t, groups[0].Rules[1].Suppressions)
+
+	batch := groups[0].Rules[2].Suppressions
+	require.Len(t, batch, 1)
+	require.Nil(t, batch[0].Selector, "a bare comment suppresses every selector of the rule")
+
+	require.Empty(t, groups[1].Rules[0].Suppressions)
+}
+
+func TestParseFileSuppressions_InvalidComment(t *testing.T) {
+	_, err := parseFileSuppressions([]byte(`groups:
+  - name: example
+    rules:
+      # promcheck:ignore up until=never
+      - record: job:up:sum
+        expr: sum(up)
+`))
+	require.ErrorContains(t, err, "rule at line 5: promcheck:ignore comment")
+}
@@ -0,0 +1,68 @@
+package checker
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+)
+
+// maxAlternatives bounds the number of alternatives of a single regex matcher
+// probed one by one, so a huge alternation doesn't fan out into as many probes.
+const maxAlternatives = 32
+
+// probeAlternatives probes every alternative of the simple alternation regex
+// matchers (e.g. job=~"api|worker|cron") of the given selectors with a result
+// value on its own, keeping all other matchers and modifiers. With a lookback
+// window configured, alternatives are probed over the window.
+// probeAlternatives records the alternatives without a result value as
+// DeadAlternatives in results, the selectors themselves keep their result.
+func (prc *PrometheusRulesChecker) probeAlternatives(ctx context.Context, ts time.Time, selectors []ruleSelector, results *selectorResults) error {
+	seen := make(map[string]struct{}, len(selectors))
+	for _, selector := range selectors {
+		if _, ok := seen[selector.text]; ok {
+			continue
+		}
+		seen[selector.text] = struct{}{}
+
+		matchers, err := prc.parser.ParseMetricSelector(selector.expr)
+		if err != nil {
+			return err
+		}
+		for i, m := range matchers {
+			for _, value := range alternatives(m) {
+				alternative := slices.Clone(matchers)
+				alternative[i] = labels.MustNewMatcher(labels.MatchEqual, m.Name, value)
+				probe := selector.withExpr(selectorString(alternative))
+				if prc.lookback > 0 {
+					probe.window = max(prc.lookback, probe.window)
+				}
+				val, err := prc.probeSelector(ctx, probe, ts)
+				if err != nil {
+					return err
+				}
+				if val < 1 {
+					r := results.get(selector.text)
+					r.DeadAlternatives = append(r.DeadAlternatives, alternative[i].String())
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// alternatives returns the values a regex matcher matches if it is a simple
+// alternation of at least two and at most maxAlternatives literal values,
+// nil otherwise. Empty values are left out, since they match series without
+// the label, which can't be probed on their own.
+func alternatives(m *labels.Matcher) []string {
+	if m.Type != labels.MatchRegexp {
+		return nil
+	}
+	values := slices.DeleteFunc(m.SetMatches(), func(v string) bool { return v == "" })
+	if len(values) < 2 || len(values) > maxAlternatives {
+		return nil
+	}
+	return values
+}
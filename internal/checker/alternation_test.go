@@ -0,0 +1,73 @@
+package checker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	promql "github.com/prometheus/prometheus/promql/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlternatives(t *testing.T) {
+	tests := []struct {
+		name    string
+		matcher *labels.Matcher
+		want    []string
+	}{
+		{"alternation", labels.MustNewMatcher(labels.MatchRegexp, "job", "api|worker|cron"), []string{"api", "worker", "cron"}},
+		{"empty alternative", labels.MustNewMatcher(labels.MatchRegexp, "job", "api|worker|"), []string{"api", "worker"}},
+		{"single value", labels.MustNewMatcher(labels.MatchRegexp, "job", "api"), nil},
+		{"wildcard", labels.MustNewMatcher(labels.MatchRegexp, "job", "api|work.*"), nil},
+		{"case insensitive", labels.MustNewMatcher(labels.MatchRegexp, "job", "(?i)api|worker"), nil},
+		{"negative", labels.MustNewMatcher(labels.MatchNotRegexp, "job", "api|worker"), nil},
+		{"equality", labels.MustNewMatcher(labels.MatchEqual, "job", "api|worker"), nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, alternatives(tt.matcher))
+		})
+	}
+}
+
+func TestCheckRule_ReportsDeadAlternatives(t *testing.T) {
+	fp := &fakeProber{values: map[string]float64{
+		`up{job=~"api|worker|cron"}`: 3,
+		`up{job="api"}`:              3,
+	}}
+	prc := &PrometheusRulesChecker{probe: fp, parser: promql.NewParser(promql.Options{}), alternations: true}
+
+	got, err := prc.checkRule(t.Context(), time.Now(), Rule{Name: "r", Expression: `up{job=~"api|worker|cron"} or down{job=~"a|b"}`})
+	require.NoError(t, err)
+	require.Equal(t, []string{`up{job=~"api|worker|cron"}`}, got.Results, "dead alternatives must not fail the selector")
+	require.Equal(t, []string{`down{job=~"a|b"}`}, got.NoResults)
+	require.Equal(t, []SelectorResult{
+		{Selector: `up{job=~"api|worker|cron"}`, Series: 3, DeadAlternatives: []string{`job="worker"`, `job="cron"`}},
+		{Selector: `down{job=~"a|b"}`},
+	}, got.Selectors)
+	require.NotContains(t, fp.calls, `down{job="a"}`, "alternatives of selectors without results are not probed")
+}
+
+func TestCheckRule_ReportsDeadAlternativesOfRepeatedSelectorsOnce(t *testing.T) {
+	fp := &fakeProber{values: map[string]float64{
+		`a{job=~"x|y"}`: 1,
+		`a{job="x"}`:    1,
+	}}
+	prc := &PrometheusRulesChecker{probe: fp, parser: promql.NewParser(promql.Options{}), alternations: true}
+
+	got, err := prc.checkRule(t.Context(), time.Now(), Rule{Name: "r", Expression: `a{job=~"x|y"} / a{job=~"x|y"}`})
+	require.NoError(t, err)
+	require.Len(t, got.Selectors, 1)
+	require.Equal(t, []string{`job="y"`}, got.Selectors[0].DeadAlternatives)
+}
+
+func TestProbeAlternatives_KeepsModifiersAndHonorsLookback(t *testing.T) {
+	fp := &fakeProber{rangeValues: map[string]float64{`up{job="api"}`: 1}}
+	prc := &PrometheusRulesChecker{probe: fp, parser: promql.NewParser(promql.Options{}), lookback: 24 * time.Hour}
+
+	selector := ruleSelector{expr: `up{job=~"api|worker"}`, text: `up{job=~"api|worker"}[5m]`, window: 5 * time.Minute}
+	results := newSelectorResults()
+	require.NoError(t, prc.probeAlternatives(t.Context(), time.Now(), []ruleSelector{selector}, results))
+	require.Equal(t, []string{`job="worker"`}, results.list()[0].DeadAlternatives)
+	require.Equal(t, map[string]time.Duration{`up{job="api"}`: 24 * time.Hour, `up{job="worker"}`: 24 * time.Hour}, fp.windows)
+}
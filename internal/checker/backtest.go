@@ -0,0 +1,138 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/prometheus/common/model"
+)
+
+const (
+	// maxBacktestPoints bounds the number of steps of a backtest's range
+	// query, staying below the 11,000 points per series Prometheus accepts.
+	maxBacktestPoints = 10000
+
+	// continuousFiringRatio represents the share of a backtest's range an
+	// alert must fire for to be considered firing nearly continuously.
+	continuousFiringRatio = 0.9
+)
+
+// Backtest represents how an alerting rule would have behaved over a range of historical data.
+type Backtest struct {
+	// Range represents the backtested window ending at the evaluation timestamp
+	Range time.Duration
+
+	// Step represents the resolution the rule's expression was evaluated at
+	Step time.Duration
+
+	// Pending represents the number of times an alert would have become pending
+	Pending int
+
+	// Firing represents the number of times an alert would have fired
+	Firing int
+
+	// PendingDuration represents how long alerts would have been pending with none firing
+	PendingDuration time.Duration
+
+	// FiringDuration represents how long at least one alert would have been firing
+	FiringDuration time.Duration
+
+	// FiringRatio represents the share of the range at least one alert would have been firing for
+	FiringRatio float64
+
+	// NeverFired reports whether no alert would have fired within the range
+	NeverFired bool
+
+	// Continuous reports whether alerts would have fired nearly continuously within the range
+	Continuous bool
+}
+
+// backtestRule evaluates the expression of an alerting rule over the
+// configured backtest range ending at the evaluation timestamp ts and replays
+// its pending and firing states, see replayAlert.
+func (prc *PrometheusRulesChecker) backtestRule(ctx context.Context, ts time.Time, rule Rule) (*Backtest, error) {
+	expr, err := prc.parser.ParseExpr(rule.Expression)
+	if err != nil {
+		return nil, fmt.Errorf("promql parse error: %w", err)
+	}
+	step := backtestStep(prc.backtest, prc.backtestStep)
+	start := ts.Add(-prc.backtest)
+	if err := prc.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer prc.release()
+	matrix, err := prc.query.QueryRange(ctx, vectorQuery(expr), start, ts, step)
+	if err != nil {
+		return nil, err
+	}
+	return replayAlert(matrix, start, prc.backtest, step, rule.For), nil
+}
+
+// backtestStep returns the given step, widened so a backtest over rng stays
+// within maxBacktestPoints steps, and to at least a second.
+func backtestStep(rng, step time.Duration) time.Duration {
+	return max(step, (rng / maxBacktestPoints).Round(time.Second), time.Second)
+}
+
+// replayAlert replays the pending and firing states of an alert whose
+// expression yielded matrix over the range rng starting at start, evaluated
+// every step. A series makes the alert pending, and it fires once the series
+// was present at every step for at least holdFor, series present at the
+// start of the range count as active since then.
+func replayAlert(matrix model.Matrix, start time.Time, rng, step, holdFor time.Duration) *Backtest {
+	steps := int(rng/step) + 1
+	var (
+		pending = make([]bool, steps)
+		firing  = make([]bool, steps)
+		bt      = &Backtest{Range: rng, Step: step}
+	)
+	for _, series := range matrix {
+		indexes := make([]int, 0, len(series.Values))
+		for _, sample := range series.Values {
+			i := int(sample.Timestamp.Time().Sub(start).Round(step) / step)
+			if i >= 0 && i < steps {
+				indexes = append(indexes, i)
+			}
+		}
+		slices.Sort(indexes)
+		indexes = slices.Compact(indexes)
+
+		activeAt, fired := -1, false
+		for j, i := range indexes {
+			if j == 0 || i != indexes[j-1]+1 {
+				// the series (re-)appeared, starting a new alert
+				activeAt, fired = i, false
+				if holdFor > 0 {
+					bt.Pending++
+				}
+			}
+			if time.Duration(i-activeAt)*step < holdFor {
+				pending[i] = true
+				continue
+			}
+			firing[i] = true
+			if !fired {
+				fired = true
+				bt.Firing++
+			}
+		}
+	}
+
+	var pendingSteps, firingSteps int
+	for i := range steps {
+		switch {
+		case firing[i]:
+			firingSteps++
+		case pending[i]:
+			pendingSteps++
+		}
+	}
+	bt.PendingDuration = time.Duration(pendingSteps) * step
+	bt.FiringDuration = time.Duration(firingSteps) * step
+	bt.FiringRatio = float64(firingSteps) / float64(steps)
+	bt.NeverFired = bt.Firing == 0
+	bt.Continuous = bt.FiringRatio >= continuousFiringRatio
+	return bt
+}
@@ -0,0 +1,102 @@
+package checker
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	promql "github.com/prometheus/prometheus/promql/parser"
+	"github.com/stretchr/testify/require"
+)
+
+// activeSeries returns a series of the given labels present at the given steps of a range starting at start.
+func activeSeries(start time.Time, step time.Duration, metric model.Metric, steps ...int) *model.SampleStream {
+	s := &model.SampleStream{Metric: metric}
+	for _, i := range steps {
+		s.Values = append(s.Values, model.SamplePair{Timestamp: model.TimeFromUnixNano(start.Add(time.Duration(i) * step).UnixNano()), Value: 1})
+	}
+	return s
+}
+
+func TestReplayAlert(t *testing.T) {
+	start := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+	step := time.Minute
+	rng := 9 * time.Minute // 10 steps
+
+	tests := []struct {
+		name    string
+		matrix  model.Matrix
+		holdFor time.Duration
+		want    Backtest
+	}{
+		{
+			name: "never active",
+			want: Backtest{Range: rng, Step: step, NeverFired: true},
+		},
+		{
+			name:    "pending without firing",
+			matrix:  model.Matrix{activeSeries(start, step, model.Metric{"pod": "a"}, 2, 3, 6)},
+			holdFor: 2 * time.Minute,
+			want:    Backtest{Range: rng, Step: step, Pending: 2, PendingDuration: 3 * time.Minute, NeverFired: true},
+		},
+		{
+			name:    "fires after for",
+			matrix:  model.Matrix{activeSeries(start, step, model.Metric{"pod": "a"}, 2, 3, 4, 5)},
+			holdFor: 2 * time.Minute,
+			want: Backtest{
+				Range: rng, Step: step, Pending: 1, Firing: 1,
+				PendingDuration: 2 * time.Minute, FiringDuration: 2 * time.Minute, FiringRatio: 0.2,
+			},
+		},
+		{
+			name: "overlapping alerts of several series",
+			matrix: model.Matrix{
+				activeSeries(start, step, model.Metric{"pod": "a"}, 0, 1, 2),
+				activeSeries(start, step, model.Metric{"pod": "b"}, 1, 2, 3, 7),
+			},
+			want: Backtest{Range: rng, Step: step, Firing: 3, FiringDuration: 5 * time.Minute, FiringRatio: 0.5},
+		},
+		{
+			name:   "firing continuously",
+			matrix: model.Matrix{activeSeries(start, step, model.Metric{"pod": "a"}, 0, 1, 2, 3, 4, 5, 6, 7, 8, 9)},
+			want:   Backtest{Range: rng, Step: step, Firing: 1, FiringDuration: 10 * time.Minute, FiringRatio: 1, Continuous: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, &tt.want, replayAlert(tt.matrix, start, rng, step, tt.holdFor))
+		})
+	}
+}
+
+func TestBacktestStep(t *testing.T) {
+	require.Equal(t, time.Minute, backtestStep(24*time.Hour, time.Minute))
+	require.Equal(t, 2*time.Minute+time.Second, backtestStep(14*24*time.Hour, time.Minute), "steps must stay within maxBacktestPoints")
+	require.Equal(t, time.Second, backtestStep(time.Hour, 0))
+}
+
+func TestCheckRule_BacktestsAlertingRulesOnly(t *testing.T) {
+	ts := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	fq := &fakeQuerier{matrices: map[string]model.Matrix{
+		`up == 0`: {activeSeries(ts.Add(-time.Hour), time.Minute, model.Metric{"job": "x"}, 10, 11, 12, 13, 14, 15)},
+	}}
+	prc := &PrometheusRulesChecker{
+		probe:        &fakeProber{values: map[string]float64{`up`: 1}},
+		query:        fq,
+		parser:       promql.NewParser(promql.Options{}),
+		backtest:     time.Hour,
+		backtestStep: time.Minute,
+	}
+
+	got, err := prc.checkRule(t.Context(), ts, Rule{Name: "InstanceDown", Expression: `up == 0`, Alerting: true, For: 5 * time.Minute})
+	require.NoError(t, err)
+	require.Equal(t, &Backtest{
+		Range: time.Hour, Step: time.Minute, Pending: 1, Firing: 1,
+		PendingDuration: 5 * time.Minute, FiringDuration: time.Minute, FiringRatio: 1.0 / 61,
+	}, got.Backtest)
+	require.Equal(t, time.Minute, fq.step)
+
+	got, err = prc.checkRule(t.Context(), ts, Rule{Name: "up:sum", Expression: `up == 0`})
+	require.NoError(t, err)
+	require.Nil(t, got.Backtest, "recording rules are not backtested")
+}
@@ -0,0 +1,180 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"golang.org/x/sync/errgroup"
+)
+
+// BatchProber represents a Prober which can probe many selectors with a single query.
+type BatchProber interface {
+	Prober
+
+	// BatchSize returns the maximum number of selectors per ProbeSelectorBatch call.
+	BatchSize() int
+
+	// ProbeSelectorBatch probes the given selectors against a remote instance
+	// with a single query evaluated at the given timestamp ts.
+	// ProbeSelectorBatch returns one result value per probe, in order.
+	ProbeSelectorBatch(ctx context.Context, probes []BatchProbe, ts time.Time) ([]float64, error)
+}
+
+// BatchProbe represents a single selector probe of a batch.
+type BatchProbe struct {
+	// Selector represents the PromQL selector to probe
+	Selector string
+
+	// Window represents the window ending at the evaluation timestamp to probe
+	// the selector over, zero probes the selector at the evaluation timestamp only
+	Window time.Duration
+}
+
+const (
+	// batchLabel tags the result of each probe of a batch query with the probe's index.
+	batchLabel = "promcheck_batch"
+
+	// maxBatchQueryLength bounds the length of a batch query in bytes.
+	maxBatchQueryLength = 32 * 1024
+)
+
+// errBatchTooLarge is returned by batchProbe if a batch query exceeds maxBatchQueryLength.
+var errBatchTooLarge = errors.New("batch query exceeds size limit")
+
+// batchProbe is a prometheusProbe which additionally probes many selectors
+// with a single query: one label_replace-tagged count() per selector, joined with "or".
+type batchProbe struct {
+	*prometheusProbe
+	size int
+}
+
+// BatchSize implements BatchProber.
+func (p *batchProbe) BatchSize() int {
+	return p.size
+}
+
+// ProbeSelectorBatch implements BatchProber.
+func (p *batchProbe) ProbeSelectorBatch(ctx context.Context, probes []BatchProbe, ts time.Time) ([]float64, error) {
+	query := batchQuery(probes)
+	if len(query) > maxBatchQueryLength {
+		return nil, errBatchTooLarge
+	}
+	value, err := p.query(ctx, query, ts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query metrics: %w", err)
+	}
+	vec, ok := value.(model.Vector)
+	if !ok {
+		return nil, fmt.Errorf("unexpected batch query result type %T (wanted vector)", value)
+	}
+	out := make([]float64, len(probes))
+	for _, sample := range vec {
+		i, err := strconv.Atoi(string(sample.Metric[batchLabel]))
+		if err != nil || i < 0 || i >= len(probes) {
+			return nil, fmt.Errorf("unexpected batch query result %s", sample.Metric)
+		}
+		if v := float64(sample.Value); !math.IsNaN(v) {
+			out[i] = v
+		}
+	}
+	return out, nil
+}
+
+// batchQuery returns a single query counting the series of each of the given
+// probes, tagging each count with the probe's index in batchLabel.
+func batchQuery(probes []BatchProbe) string {
+	parts := make([]string, 0, len(probes))
+	for i, probe := range probes {
+		expr := probe.Selector
+		if probe.Window > 0 {
+			expr = fmt.Sprintf("last_over_time(%s[%s])", probe.Selector, model.Duration(probe.Window))
+		}
+		parts = append(parts, fmt.Sprintf(`label_replace(count(%s), %q, "%d", "", "")`, expr, batchLabel, i))
+	}
+	return strings.Join(parts, " or ")
+}
+
+// prefetchBatches probes the selectors of all given rules in batches and
+// stores the results in the run's probe cache, so the per-rule checks that
+// follow are answered from the cache. Batches which fail or exceed the size
+// limit are skipped, leaving their selectors to be probed one by one.
+// prefetchBatches does nothing unless the prober is a BatchProber and a run was started.
+func (prc *PrometheusRulesChecker) prefetchBatches(ctx context.Context, ts time.Time, rules []Rule) error {
+	bp, ok := prc.probe.(BatchProber)
+	run := prc.currentRun()
+	if !ok || run == nil || bp.BatchSize() < 1 {
+		return nil
+	}
+
+	// batches can only share an evaluation timestamp, so group probes by it
+	type pending struct {
+		keys   []probeKey
+		probes []BatchProbe
+	}
+	byTs := map[int64]*pending{}
+	seen := map[probeKey]struct{}{}
+	for _, rule := range rules {
+		selectors, err := getRuleSelectors(prc.parser, rule.Expression)
+		if err != nil {
+			// reported by the rule's own check
+			continue
+		}
+		for _, selector := range selectors {
+			skip, err := prc.skipSelector(selector)
+			if err != nil || skip {
+				continue
+			}
+			key := probeKey{selector: prc.normalizeSelector(selector.expr), ts: selector.evalTime(ts).UnixMilli(), window: selector.window}
+			if _, ok := seen[key]; ok || run.cache.has(key) {
+				continue
+			}
+			seen[key] = struct{}{}
+			p := byTs[key.ts]
+			if p == nil {
+				p = &pending{}
+				byTs[key.ts] = p
+			}
+			p.keys = append(p.keys, key)
+			p.probes = append(p.probes, BatchProbe{Selector: selector.expr, Window: selector.window})
+		}
+	}
+
+	eg, ctx := errgroup.WithContext(ctx)
+	for _, millis := range slices.Sorted(maps.Keys(byTs)) {
+		p := byTs[millis]
+		for start := 0; start < len(p.probes); start += bp.BatchSize() {
+			end := min(start+bp.BatchSize(), len(p.probes))
+			keys, probes := p.keys[start:end], p.probes[start:end]
+			eg.Go(func() error {
+				if err := prc.acquire(ctx); err != nil {
+					return err
+				}
+				batchCtx, warnings := withWarnings(ctx)
+				values, err := bp.ProbeSelectorBatch(batchCtx, probes, time.UnixMilli(millis))
+				prc.release()
+				if err != nil {
+					if ctxErr := ctx.Err(); ctxErr != nil {
+						return ctxErr
+					}
+					run.cache.addBatch(false)
+					return nil
+				}
+				run.cache.addBatch(true)
+				// the warnings of a batch query can't be told apart by selector
+				for i, key := range keys {
+					run.cache.store(key, probeResult{value: values[i], warnings: warnings.list()})
+				}
+				return nil
+			})
+		}
+	}
+	return eg.Wait()
+}
@@ -0,0 +1,104 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	promql "github.com/prometheus/prometheus/promql/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_batchQuery(t *testing.T) {
+	got := batchQuery([]BatchProbe{
+		{Selector: `up{job="x"}`},
+		{Selector: `batch_job_success`, Window: 24 * time.Hour},
+	})
+	require.Equal(t, `label_replace(count(up{job="x"}), "promcheck_batch", "0", "", "") or `+
+		`label_replace(count(last_over_time(batch_job_success[1d])), "promcheck_batch", "1", "", "")`, got)
+}
+
+func TestBatchProbe_DemultiplexesResults(t *testing.T) {
+	api := &fakeAPI{value: model.Vector{
+		&model.Sample{Metric: model.Metric{batchLabel: "2"}, Value: 7},
+		&model.Sample{Metric: model.Metric{batchLabel: "0"}, Value: 3},
+	}}
+	p := &batchProbe{prometheusProbe: &prometheusProbe{api: api}, size: 10}
+
+	got, err := p.ProbeSelectorBatch(context.Background(), []BatchProbe{{Selector: `a`}, {Selector: `b`}, {Selector: `c`}}, time.Now())
+	require.NoError(t, err)
+	require.Equal(t, []float64{3, 0, 7}, got)
+}
+
+func TestBatchProbe_RejectsOversizedQueries(t *testing.T) {
+	api := &fakeAPI{value: model.Vector{}}
+	p := &batchProbe{prometheusProbe: &prometheusProbe{api: api}, size: 10}
+
+	_, err := p.ProbeSelectorBatch(context.Background(), []BatchProbe{{Selector: strings.Repeat("a", maxBatchQueryLength)}}, time.Now())
+	require.ErrorIs(t, err, errBatchTooLarge)
+	require.Empty(t, api.gotQuery, "oversized batches must not be sent")
+}
+
+// fakeBatchProber is a test helper implementing BatchProber interface
+type fakeBatchProber struct {
+	*fakeProber
+	size int
+	// err, if set, is returned for every batch
+	err error
+
+	mu sync.Mutex
+	// batches records the selectors of every batch, in order
+	batches [][]string
+}
+
+func (f *fakeBatchProber) BatchSize() int { return f.size }
+
+func (f *fakeBatchProber) ProbeSelectorBatch(_ context.Context, probes []BatchProbe, _ time.Time) ([]float64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	batch := make([]string, 0, len(probes))
+	out := make([]float64, 0, len(probes))
+	for _, p := range probes {
+		batch = append(batch, p.Selector)
+		out = append(out, f.values[p.Selector])
+	}
+	f.batches = append(f.batches, batch)
+	if f.err != nil {
+		return nil, f.err
+	}
+	return out, nil
+}
+
+func TestCheckRuleGroup_PrefetchesSelectorsInBatches(t *testing.T) {
+	fp := &fakeBatchProber{fakeProber: &fakeProber{values: map[string]float64{`up`: 1, `node_load1`: 4}}, size: 2}
+	prc := &PrometheusRulesChecker{probe: fp, parser: promql.NewParser(promql.Options{})}
+	prc.StartRun()
+
+	got, err := prc.CheckRuleGroup(t.Context(), RuleGroup{Name: "g", Rules: []Rule{
+		{Name: "r", Expression: `up or node_load1 or gone or up or ALERTS`},
+	}})
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	require.Equal(t, []string{`up`, `node_load1`, `up`}, got[0].Results)
+	require.Equal(t, []string{`gone`}, got[0].NoResults)
+	require.ElementsMatch(t, [][]string{{`up`, `node_load1`}, {`gone`}}, fp.batches)
+	require.Empty(t, fp.calls, "batched selectors must not be probed one by one")
+	require.Equal(t, ProbeStats{Hits: 4, Batches: 2}, prc.ProbeStats())
+}
+
+func TestCheckRuleGroup_FallsBackToSingleProbesOnBatchError(t *testing.T) {
+	fp := &fakeBatchProber{fakeProber: &fakeProber{values: map[string]float64{`up`: 1}}, size: 10, err: errors.New("query too expensive")}
+	prc := &PrometheusRulesChecker{probe: fp, parser: promql.NewParser(promql.Options{})}
+	prc.StartRun()
+
+	got, err := prc.CheckRuleGroup(t.Context(), RuleGroup{Name: "g", Rules: []Rule{{Name: "r", Expression: `up or gone`}}})
+	require.NoError(t, err)
+	require.Equal(t, []string{`up`}, got[0].Results)
+	require.Equal(t, []string{`gone`}, got[0].NoResults)
+	require.Equal(t, []string{`up`, `gone`}, fp.calls)
+	require.Equal(t, ProbeStats{Misses: 2, BatchFallbacks: 1}, prc.ProbeStats())
+}
@@ -0,0 +1,316 @@
+package checker
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	"golang.org/x/sync/singleflight"
+)
+
+// ProbeStats represents the probe cache, retry and rate limit statistics of a check run.
+type ProbeStats struct {
+	// Hits represents the number of probes answered from the cache
+	Hits int
+
+	// Shared represents the number of probes which joined an identical in-flight probe
+	Shared int
+
+	// Misses represents the number of probes sent to the remote instance
+	Misses int
+
+	// Batches represents the number of batch queries which prefetched probe results
+	Batches int
+
+	// BatchFallbacks represents the number of batch queries which failed or exceeded
+	// the size limit, leaving their selectors to be probed one by one
+	BatchFallbacks int
+
+	// Retries represents the number of retried remote calls by the class of
+	// the error they were retried after ("network" or "server")
+	Retries map[string]int
+
+	// RateBackoffs represents the number of times the adaptive rate limit was halved
+	RateBackoffs int
+}
+
+// checkRun represents the state shared by all rule groups checked in a single run.
+type checkRun struct {
+	// ts represents the evaluation timestamp of the run
+	ts time.Time
+
+	// cache deduplicates identical probes across all rule groups of the run
+	cache *probeCache
+
+	// recordings represents the recording rules of the run, nil unless
+	// recording rules are resolved
+	recordings recordingRules
+
+	// metricNames caches the metric names suggestions are looked up in
+	metricNames metricNameCache
+}
+
+// probeKey identifies a probe: the normalized selector, the timestamp it is
+// evaluated at (including offset and @ modifiers) and its window (zero for instant probes).
+type probeKey struct {
+	selector string
+	ts       int64
+	window   time.Duration
+}
+
+// String returns the key identifying the flight of the probe.
+func (k probeKey) String() string {
+	return fmt.Sprintf("%s\x00%d\x00%d", k.selector, k.ts, k.window)
+}
+
+// probeResult represents the cached result of a probe.
+type probeResult struct {
+	// value represents the probe's result value
+	value float64
+
+	// warnings represents the warnings Prometheus returned along with the result value
+	warnings []string
+}
+
+// probeCache caches probe results for the duration of a run. Concurrent
+// identical probes share a single in-flight query. Failed probes are not cached.
+type probeCache struct {
+	flights flightGroup
+
+	mu      sync.Mutex
+	results map[probeKey]probeResult
+	stats   ProbeStats
+}
+
+func newProbeCache() *probeCache {
+	return &probeCache{results: map[probeKey]probeResult{}}
+}
+
+// do returns the cached result for key, or calls probe to get it, see
+// flightGroup.do for the context probe is called with.
+func (c *probeCache) do(ctx context.Context, key probeKey, probe func(ctx context.Context) (probeResult, error)) (probeResult, error) {
+	c.mu.Lock()
+	if res, ok := c.results[key]; ok {
+		c.stats.Hits++
+		c.mu.Unlock()
+		return res, nil
+	}
+	c.mu.Unlock()
+
+	var called bool
+	v, err, shared := c.flights.do(ctx, key.String(), func(ctx context.Context) (any, error) {
+		// an identical probe may have completed since the lookup above
+		c.mu.Lock()
+		res, ok := c.results[key]
+		c.mu.Unlock()
+		if ok {
+			return res, nil
+		}
+		called = true
+		res, err := probe(ctx)
+		if err != nil {
+			return probeResult{}, err
+		}
+		c.mu.Lock()
+		c.results[key] = res
+		c.mu.Unlock()
+		return res, nil
+	})
+	if v == nil {
+		// the caller gave up waiting for the probe
+		return probeResult{}, err
+	}
+
+	c.mu.Lock()
+	switch {
+	case called:
+		c.stats.Misses++
+	case shared:
+		c.stats.Shared++
+	default:
+		c.stats.Hits++
+	}
+	c.mu.Unlock()
+	return v.(probeResult), err
+}
+
+// flightGroup shares a single in-flight call among concurrent callers with
+// the same key, like singleflight.Group, but detaches the call from the
+// context of the caller which started it: the call's context is only
+// cancelled once every caller waiting for it gave up, so one caller hitting
+// its deadline doesn't fail the others.
+type flightGroup struct {
+	group singleflight.Group
+
+	mu      sync.Mutex
+	flights map[string]*flight
+}
+
+// flight represents an in-flight call of a flightGroup.
+type flight struct {
+	// ctx represents the context the call runs with
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// waiters represents the number of callers waiting for the call
+	waiters int
+}
+
+// do calls fn with the context of the in-flight call for key, unless there
+// is one already, and waits for its result or for ctx to be done, whichever
+// comes first. If ctx is done first, do returns a nil value and ctx's error.
+// shared reports whether the result was handed to more than one caller.
+func (g *flightGroup) do(ctx context.Context, key string, fn func(ctx context.Context) (any, error)) (v any, err error, shared bool) {
+	f := g.join(ctx, key)
+	defer g.leave(key, f)
+
+	ch := g.group.DoChan(key, func() (any, error) {
+		return fn(f.ctx)
+	})
+	select {
+	case res := <-ch:
+		return res.Val, res.Err, res.Shared
+	case <-ctx.Done():
+		return nil, ctx.Err(), false
+	}
+}
+
+// join registers a caller waiting for the call for key, starting a new
+// flight if there is none.
+func (g *flightGroup) join(ctx context.Context, key string) *flight {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.flights == nil {
+		g.flights = map[string]*flight{}
+	}
+	f, ok := g.flights[key]
+	if !ok {
+		// keep the values of ctx, e.g. its warningCollector, but not its cancellation
+		flightCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		f = &flight{ctx: flightCtx, cancel: cancel}
+		g.flights[key] = f
+	}
+	f.waiters++
+	return f
+}
+
+// leave unregisters a caller waiting for the call for key. Once the last
+// caller left, the call is cancelled and forgotten, so later callers start
+// a new one instead of joining the cancelled one.
+func (g *flightGroup) leave(key string, f *flight) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	f.waiters--
+	if f.waiters > 0 {
+		return
+	}
+	f.cancel()
+	delete(g.flights, key)
+	g.group.Forget(key)
+}
+
+// has reports whether there is a cached result for key.
+func (c *probeCache) has(key probeKey) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.results[key]
+	return ok
+}
+
+// store caches res as the result for key.
+func (c *probeCache) store(key probeKey, res probeResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.results[key] = res
+}
+
+// addBatch records the outcome of a batch query in the statistics.
+func (c *probeCache) addBatch(ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if ok {
+		c.stats.Batches++
+		return
+	}
+	c.stats.BatchFallbacks++
+}
+
+// snapshot returns the cache statistics collected so far.
+func (c *probeCache) snapshot() ProbeStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.stats
+}
+
+// StartRun starts a new check run: all rule groups checked until the next call
+// are evaluated at the same timestamp and share a fresh probe cache, so
+// selectors referenced by many rules are probed only once. The recording
+// rules of the given groups are resolved by selectors of the run referring to
+// them, see PrometheusRulesCheckerConfig.RecordingRules.
+func (prc *PrometheusRulesChecker) StartRun(groups ...RuleGroup) {
+	prc.runMu.Lock()
+	defer prc.runMu.Unlock()
+	prc.run = &checkRun{ts: time.Now(), cache: newProbeCache()}
+	if prc.recordingRules {
+		prc.run.recordings = newRecordingRules(groups)
+	}
+	prc.retry.reset()
+	prc.limit.resetBackoffs()
+}
+
+// ProbeStats returns the probe cache, retry and rate limit statistics of the current run.
+func (prc *PrometheusRulesChecker) ProbeStats() ProbeStats {
+	var stats ProbeStats
+	if run := prc.currentRun(); run != nil {
+		stats = run.cache.snapshot()
+	}
+	stats.Retries = prc.retry.snapshot()
+	stats.RateBackoffs = prc.limit.backoffCount()
+	return stats
+}
+
+// currentRun returns the current run, nil if StartRun was never called.
+func (prc *PrometheusRulesChecker) currentRun() *checkRun {
+	prc.runMu.Lock()
+	defer prc.runMu.Unlock()
+	return prc.run
+}
+
+// cachedProbe deduplicates the given probe of selector at ts over window
+// through the current run's cache, if there is a run. The warnings of the
+// probe, cached or not, are recorded in the warningCollector of ctx.
+func (prc *PrometheusRulesChecker) cachedProbe(ctx context.Context, selector string, ts time.Time, window time.Duration, probe func(ctx context.Context) (float64, error)) (float64, error) {
+	run := prc.currentRun()
+	if run == nil {
+		return probe(ctx)
+	}
+	key := probeKey{selector: prc.normalizeSelector(selector), ts: ts.UnixMilli(), window: window}
+	res, err := run.cache.do(ctx, key, func(ctx context.Context) (probeResult, error) {
+		probeCtx, warnings := withWarnings(ctx)
+		val, err := probe(probeCtx)
+		return probeResult{value: val, warnings: warnings.list()}, err
+	})
+	addWarnings(ctx, res.warnings)
+	return res.value, err
+}
+
+// normalizeSelector returns selector with its label matchers in a canonical
+// order, so equivalent selectors written differently share cache entries.
+func (prc *PrometheusRulesChecker) normalizeSelector(selector string) string {
+	matchers, err := prc.parser.ParseMetricSelector(selector)
+	if err != nil {
+		return selector
+	}
+	slices.SortFunc(matchers, func(a, b *labels.Matcher) int {
+		return cmp.Or(
+			cmp.Compare(a.Name, b.Name),
+			cmp.Compare(a.Type, b.Type),
+			cmp.Compare(a.Value, b.Value),
+		)
+	})
+	return selectorString(matchers)
+}
@@ -0,0 +1,137 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	promql "github.com/prometheus/prometheus/promql/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckRule_CachesProbesWithinRun(t *testing.T) {
+	fp := &fakeProber{values: map[string]float64{`up{instance="a",job="x"}`: 1}}
+	prc := &PrometheusRulesChecker{probe: fp, parser: promql.NewParser(promql.Options{})}
+	prc.StartRun()
+	ts := prc.currentRun().ts
+
+	_, err := prc.checkRule(t.Context(), ts, Rule{Name: "a", Expression: `up{instance="a",job="x"}`})
+	require.NoError(t, err)
+	got, err := prc.checkRule(t.Context(), ts, Rule{Name: "b", Expression: `{__name__="up",job="x",instance="a"} or up{job="x",instance="a"} offset 1h`})
+	require.NoError(t, err)
+
+	require.Equal(t, []string{`{__name__="up",instance="a",job="x"}`, `up{instance="a",job="x"} offset 1h`}, got.Results, "cached results must be reported for the selectors as written")
+	require.Equal(t, []string{`up{instance="a",job="x"}`, `up{instance="a",job="x"}`}, fp.calls, "equivalent selectors must share a probe, a different offset must not")
+	require.Equal(t, []time.Time{ts, ts.Add(-time.Hour)}, fp.tsCalls)
+	require.Equal(t, ProbeStats{Hits: 1, Misses: 2}, prc.ProbeStats())
+
+	prc.StartRun()
+	require.Equal(t, ProbeStats{}, prc.ProbeStats(), "every run must start with a fresh cache")
+}
+
+func TestCheckRule_NoCacheWithoutRun(t *testing.T) {
+	fp := &fakeProber{values: map[string]float64{`up`: 1}}
+	prc := &PrometheusRulesChecker{probe: fp, parser: promql.NewParser(promql.Options{})}
+
+	_, err := prc.checkRule(t.Context(), time.Now(), Rule{Name: "r", Expression: `up or up`})
+	require.NoError(t, err)
+	require.Len(t, fp.calls, 2)
+}
+
+func TestProbeCache_SharesInFlightProbes(t *testing.T) {
+	c := newProbeCache()
+	key := probeKey{selector: `up`, ts: 1}
+	release := make(chan struct{})
+	var calls atomic.Int32
+
+	var joined, done sync.WaitGroup
+	results := make([]float64, 5)
+	errs := make([]error, len(results))
+	for i := range results {
+		joined.Add(1)
+		done.Go(func() {
+			joined.Done()
+			res, err := c.do(t.Context(), key, func(context.Context) (probeResult, error) {
+				calls.Add(1)
+				<-release
+				return probeResult{value: 3}, nil
+			})
+			results[i], errs[i] = res.value, err
+		})
+	}
+	// callers not joining the in-flight probe in time are answered from the cache
+	joined.Wait()
+	close(release)
+	done.Wait()
+
+	require.Equal(t, make([]error, len(results)), errs)
+	require.Equal(t, int32(1), calls.Load())
+	require.Equal(t, []float64{3, 3, 3, 3, 3}, results)
+	stats := c.snapshot()
+	require.Equal(t, 1, stats.Misses)
+	require.Equal(t, 4, stats.Hits+stats.Shared)
+}
+
+func TestProbeCache_DoesNotCacheErrors(t *testing.T) {
+	c := newProbeCache()
+	key := probeKey{selector: `up`, ts: 1}
+
+	_, err := c.do(t.Context(), key, func(context.Context) (probeResult, error) { return probeResult{}, errors.New("boom") })
+	require.Error(t, err)
+	res, err := c.do(t.Context(), key, func(context.Context) (probeResult, error) { return probeResult{value: 1}, nil })
+	require.NoError(t, err)
+	require.Equal(t, float64(1), res.value)
+	require.Equal(t, ProbeStats{Misses: 2}, c.snapshot())
+}
+
+func TestProbeCache_CancelledCallerDoesNotFailJoiners(t *testing.T) {
+	c := newProbeCache()
+	key := probeKey{selector: `up`, ts: 1}
+	started, release := make(chan struct{}), make(chan struct{})
+
+	leaderCtx, cancel := context.WithCancel(t.Context())
+	leaderErr := make(chan error, 1)
+	go func() {
+		_, err := c.do(leaderCtx, key, func(ctx context.Context) (probeResult, error) {
+			close(started)
+			<-release
+			return probeResult{value: 3}, ctx.Err()
+		})
+		leaderErr <- err
+	}()
+	<-started
+
+	type result struct {
+		res probeResult
+		err error
+	}
+	joined := make(chan result, 1)
+	go func() {
+		res, err := c.do(t.Context(), key, func(context.Context) (probeResult, error) {
+			return probeResult{}, errors.New("joiner must not start its own probe")
+		})
+		joined <- result{res: res, err: err}
+	}()
+	require.Eventually(t, func() bool { return waiters(c, key) == 2 }, time.Second, time.Millisecond)
+
+	cancel()
+	require.ErrorIs(t, <-leaderErr, context.Canceled)
+	close(release)
+	got := <-joined
+	require.NoError(t, got.err)
+	require.Equal(t, float64(3), got.res.value)
+	require.Equal(t, 0, waiters(c, key))
+}
+
+// waiters returns the number of callers waiting for the in-flight probe of key.
+func waiters(c *probeCache, key probeKey) int {
+	c.flights.mu.Lock()
+	defer c.flights.mu.Unlock()
+	if f, ok := c.flights.flights[key.String()]; ok {
+		return f.waiters
+	}
+	return 0
+}
@@ -0,0 +1,62 @@
+package checker
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// seriesThreshold represents a cardinality threshold for the selectors matching pattern.
+type seriesThreshold struct {
+	pattern *regexp.Regexp
+	limit   int
+}
+
+// parseSeriesThresholds parses per-selector cardinality thresholds of the
+// form "<selector regexp>=<limit>". The limit follows the last "=", so
+// patterns may contain label matchers.
+func parseSeriesThresholds(thresholds []string) ([]seriesThreshold, error) {
+	if len(thresholds) == 0 {
+		return nil, nil
+	}
+	parsed := make([]seriesThreshold, 0, len(thresholds))
+	for _, t := range thresholds {
+		i := strings.LastIndex(t, "=")
+		if i < 0 {
+			return nil, fmt.Errorf("%q: want <selector regexp>=<limit>", t)
+		}
+		limit, err := strconv.Atoi(t[i+1:])
+		if err != nil || limit < 0 {
+			return nil, fmt.Errorf("%q: limit must be a non-negative integer", t)
+		}
+		re, err := regexp.Compile(t[:i])
+		if err != nil {
+			return nil, fmt.Errorf("%q: %w", t, err)
+		}
+		parsed = append(parsed, seriesThreshold{pattern: re, limit: limit})
+	}
+	return parsed, nil
+}
+
+// seriesLimit returns the cardinality threshold for the given selector: the
+// first matching per-selector threshold, or else the default MaxSeries.
+// seriesLimit returns zero if there is no threshold.
+func (prc *PrometheusRulesChecker) seriesLimit(selector string) int {
+	for _, t := range prc.seriesThresholds {
+		if t.pattern.MatchString(selector) {
+			return t.limit
+		}
+	}
+	return prc.maxSeries
+}
+
+// recordSeries records the number of series val the selector yielded in r,
+// along with the cardinality threshold it exceeds, if any.
+func (prc *PrometheusRulesChecker) recordSeries(r *SelectorResult, selector ruleSelector, val float64) {
+	r.Series = int(val)
+	r.SeriesLimit = 0
+	if limit := prc.seriesLimit(selector.expr); limit > 0 && r.Series > limit {
+		r.SeriesLimit = limit
+	}
+}
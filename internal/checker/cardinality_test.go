@@ -0,0 +1,43 @@
+package checker
+
+import (
+	"testing"
+	"time"
+
+	promql "github.com/prometheus/prometheus/promql/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_parseSeriesThresholds(t *testing.T) {
+	got, err := parseSeriesThresholds([]string{`kube_pod_info{namespace="default"}=500`, `kube_.*=5000`})
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	require.Equal(t, `kube_pod_info{namespace="default"}`, got[0].pattern.String())
+	require.Equal(t, 500, got[0].limit)
+	require.Equal(t, 5000, got[1].limit)
+
+	for _, invalid := range []string{`kube_.*`, `kube_.*=many`, `kube_.*=-1`, `kube_(=10`} {
+		_, err := parseSeriesThresholds([]string{invalid})
+		require.Error(t, err, invalid)
+	}
+}
+
+func TestCheckRule_FlagsSelectorsAboveSeriesLimit(t *testing.T) {
+	thresholds, err := parseSeriesThresholds([]string{`^kube_pod_info$=5000`})
+	require.NoError(t, err)
+	fp := &fakeProber{values: map[string]float64{`kube_pod_info`: 1423, `up`: 120, `node_load1`: 80}}
+	prc := &PrometheusRulesChecker{
+		probe:            fp,
+		parser:           promql.NewParser(promql.Options{}),
+		maxSeries:        100,
+		seriesThresholds: thresholds,
+	}
+
+	got, err := prc.checkRule(t.Context(), time.Now(), Rule{Name: "r", Expression: `kube_pod_info or up or node_load1`})
+	require.NoError(t, err)
+	require.Equal(t, []SelectorResult{
+		{Selector: `kube_pod_info`, Series: 1423},
+		{Selector: `up`, Series: 120, SeriesLimit: 100},
+		{Selector: `node_load1`, Series: 80},
+	}, got.Selectors)
+}
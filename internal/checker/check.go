@@ -2,6 +2,7 @@ package checker
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"regexp"
 	"slices"
@@ -32,6 +33,102 @@ type PrometheusRulesCheckerConfig struct {
 	// MaxConcurrency bounds the total number of concurrent selector probes across
 	// all rule groups and rules. A value <= 0 means unbounded.
 	MaxConcurrency int
+
+	// DrillDown enables re-probing selectors without results with their label
+	// matchers removed one at a time to find the culprit matcher
+	DrillDown bool
+
+	// Suggestions enables "did you mean" suggestions for missing metric names
+	// and label values found by the drill-down
+	Suggestions bool
+
+	// Alternations enables probing every alternative of simple alternation
+	// regex matchers (e.g. job=~"api|worker") of selectors with a result value
+	// on its own, to find alternatives which don't match anything anymore
+	Alternations bool
+
+	// Evaluate enables evaluating the whole expression of every rule in
+	// addition to probing its selectors
+	Evaluate bool
+
+	// Backtest represents the range of historical data alerting rules are
+	// backtested over, ending at the evaluation timestamp. Zero disables it.
+	Backtest time.Duration
+
+	// BacktestStep represents the resolution of backtests, widened if the
+	// range would take more than 10,000 steps
+	BacktestStep time.Duration
+
+	// Joins enables evaluating both operands of binary operations with
+	// vector matching to find joins whose operands never match
+	Joins bool
+
+	// RecordingRules enables checking the source selectors of the recording
+	// rules of a run, see StartRun, instead of the metric they record if it
+	// has no result value, e.g. because the recording rule isn't deployed yet
+	RecordingRules bool
+
+	// GroupingLabels enables probing selectors with a result value for series
+	// carrying the labels of the by, on and group_left/group_right clauses
+	// applying to them
+	GroupingLabels bool
+
+	// Lookback represents the window selectors without a result value at the
+	// evaluation timestamp are probed over again, to tolerate intermittent
+	// metrics. Zero disables lookback probes.
+	Lookback time.Duration
+
+	// MaxAge represents the freshness threshold: selectors whose newest sample
+	// is older than MaxAge are flagged as stale. Zero disables last seen probes.
+	MaxAge time.Duration
+
+	// MaxSeries represents the default cardinality threshold: selectors
+	// yielding more series are reported with a warning. Zero disables it.
+	MaxSeries int
+
+	// MaxSeriesSelectors represents per-selector cardinality thresholds of the
+	// form "<selector regexp>=<limit>", taking precedence over MaxSeries
+	MaxSeriesSelectors []string
+
+	// BatchSize selects a prober which probes up to BatchSize selectors with a
+	// single query. A value <= 1 probes every selector with its own query.
+	BatchSize int
+
+	// Prober selects how selectors are probed, ProberQuery (the default if
+	// empty) or ProberSeries
+	Prober string
+
+	// QueryTimeout represents the evaluation timeout passed to Prometheus
+	// with every query, zero uses the Prometheus default
+	QueryTimeout time.Duration
+
+	// SeriesLimit represents the maximum number of series the series prober
+	// asks Prometheus for per probe, zero is unlimited. Series counts of the
+	// series prober are capped at the limit.
+	SeriesLimit uint64
+
+	// QPS represents the maximum rate of queries per second across all
+	// probes, zero is unlimited
+	QPS float64
+
+	// Burst represents the number of queries which may exceed QPS at once
+	Burst int
+
+	// AdaptiveQPS halves the query rate whenever Prometheus responds slowly
+	// or with 429/503, and restores it as responses are fast again
+	AdaptiveQPS bool
+
+	// Retries represents the number of times a remote call failing with a
+	// transient error is retried, zero disables retries
+	Retries int
+
+	// RetryBackoff represents the delay before the first retry, doubled on
+	// every further retry and randomized by up to half of it
+	RetryBackoff time.Duration
+
+	// ContinueOnError records query and parse errors per rule in
+	// CheckResult.Error instead of aborting the whole rule group
+	ContinueOnError bool
 }
 
 // PrometheusRulesChecker represents linting PromQL logic.
@@ -39,15 +136,43 @@ type PrometheusRulesChecker struct {
 	// probe implements Prober
 	probe Prober
 
+	// query implements Querier, a nil query disables analyses that need it
+	query Querier
+
 	// parser is the shared PromQL parser used for expression and selector parsing
 	parser promql.Parser
 
 	// options
 	ignoredSelectorsRegexp []*regexp.Regexp
 	ignoredGroupsRegexp    []*regexp.Regexp
+	drillDown              bool
+	suggestions            bool
+	alternations           bool
+	groupingLabels         bool
+	joins                  bool
+	recordingRules         bool
+	evaluate               bool
+	backtest               time.Duration
+	backtestStep           time.Duration
+	lookback               time.Duration
+	maxAge                 time.Duration
+	maxSeries              int
+	seriesThresholds       []seriesThreshold
+	continueOnError        bool
 
 	// sem bounds the total number of concurrent probes. A nil sem means unbounded.
 	sem chan struct{}
+
+	// retry retries failed remote calls of probe and query, a nil retry disables retries
+	retry *retrier
+
+	// limit rate limits the remote calls of probe and query, a nil limit does not limit
+	limit *rateLimiter
+
+	// run represents the current check run, see StartRun. A nil run means
+	// every group is evaluated at its own timestamp without a probe cache.
+	runMu sync.Mutex
+	run   *checkRun
 }
 
 // RuleGroup models a rule group that contains a set of recording and alerting rules.
@@ -63,6 +188,10 @@ type RuleGroup struct {
 	// the current time).
 	QueryOffset time.Duration `json:"queryOffset,omitempty"`
 
+	// Interval represents the group's evaluation interval, zero if unknown
+	// or the global default applies
+	Interval time.Duration `json:"interval,omitempty"`
+
 	// Rules represents a list of Rule
 	Rules []Rule `json:"rules"`
 }
@@ -74,6 +203,27 @@ type Rule struct {
 
 	// Expression represents the PromQL expression string
 	Expression string `json:"expr"`
+
+	// Alerting reports whether the rule is an alerting rule
+	Alerting bool `json:"alerting,omitempty"`
+
+	// For represents the duration an alerting rule's condition must hold
+	// before it fires, zero if it fires right away
+	For time.Duration `json:"for,omitempty"`
+
+	// KeepFiringFor represents the duration an alerting rule keeps firing
+	// after its condition cleared
+	KeepFiringFor time.Duration `json:"keepFiringFor,omitempty"`
+
+	// Labels represents the labels the rule adds to its results
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Annotations represents the annotations of an alerting rule
+	Annotations map[string]string `json:"annotations,omitempty"`
+
+	// Suppressions represents the suppressions of the rule in addition to
+	// those of its SuppressionAnnotation, e.g. from rule file comments
+	Suppressions []Suppression `json:"-"`
 }
 
 // CheckResult represents a check result.
@@ -90,11 +240,137 @@ type CheckResult struct {
 	// Expression represents the PromQL expression string
 	Expression string
 
+	// Interval represents the evaluation interval of the rule's group, zero if unknown
+	Interval time.Duration
+
+	// Alerting reports whether the rule is an alerting rule
+	Alerting bool
+
+	// For represents the duration an alerting rule's condition must hold before it fires
+	For time.Duration
+
+	// KeepFiringFor represents the duration an alerting rule keeps firing after its condition cleared
+	KeepFiringFor time.Duration
+
+	// Labels represents the labels the rule adds to its results
+	Labels map[string]string
+
+	// Annotations represents the annotations of an alerting rule
+	Annotations map[string]string
+
 	// Results represents a list of PromQL selectors which successfully returned a result value
 	Results []string
 
 	// NoResults represents a list of PromQL selectors which did not return any result value
 	NoResults []string
+
+	// ExpectedEmpty represents a list of PromQL selectors which did not
+	// return any result value, but are expected to at times, like the
+	// selectors within absent() or on the right-hand side of unless
+	ExpectedEmpty []string
+
+	// Suppressed represents the PromQL selectors which did not return any
+	// result value, but whose findings are suppressed by the rule
+	Suppressed []SuppressedSelector
+
+	// Selectors represents additional findings for individual selectors of the rule
+	Selectors []SelectorResult
+
+	// EmptyJoins represents the binary operations of the rule whose operands
+	// both yield series, but never match
+	EmptyJoins []EmptyJoin
+
+	// Status represents the outcome of evaluating the rule's whole
+	// expression, empty if not evaluated
+	Status RuleStatus
+
+	// Series represents the number of series the rule's whole expression yielded when evaluated
+	Series int
+
+	// Backtest represents how the alerting rule would have behaved over the
+	// backtest range, nil if not backtested
+	Backtest *Backtest
+
+	// Error represents the query or parse error the rule could not be checked
+	// because of, nil if the rule was checked. Only set with ContinueOnError.
+	Error error
+
+	// Unchecked reports whether the rule could not be checked before the
+	// deadline of the context passed to CheckRuleGroup was exceeded
+	Unchecked bool
+}
+
+// SelectorResult represents additional findings for a single probed selector.
+type SelectorResult struct {
+	// Selector represents the probed PromQL selector
+	Selector string
+
+	// Presence represents whether the selector returned a result value at the
+	// evaluation timestamp or only within the lookback window, empty if no
+	// lookback window is configured
+	Presence Presence
+
+	// LastSeen represents the timestamp of the selector's newest sample, zero
+	// if unknown or if there is no sample within the probed window
+	LastSeen time.Time
+
+	// Stale reports whether the selector's newest sample is older than the
+	// configured MaxAge, or there is none within NotSeenWithin
+	Stale bool
+
+	// NotSeenWithin represents the window searched for the selector's newest
+	// sample without finding any, zero if one was found or none was searched
+	NotSeenWithin time.Duration
+
+	// Series represents the number of series the selector yielded when probed
+	Series int
+
+	// SeriesLimit represents the cardinality threshold the selector exceeds, zero if within its threshold
+	SeriesLimit int
+
+	// NotRecorded reports whether the selector refers to a recording rule of
+	// the run which didn't record any series, while the source selectors of
+	// the recording rule all return a result value. The selector counts as
+	// returning a result value.
+	NotRecorded bool
+
+	// DependencyChains represents the paths from the selector, which refers to
+	// a recording rule of the run without a result value, through the source
+	// selectors of the recording rules down to the ones without a result
+	// value, e.g. [job:foo:rate5m foo_total]
+	DependencyChains [][]string
+
+	// Diagnosis explains why the selector did not return a result value, nil if not drilled down
+	Diagnosis *Diagnosis
+
+	// MissingLabels represents the labels of the grouping and matching
+	// clauses applying to the selector which not all of its series carry
+	MissingLabels []MissingLabel
+
+	// DeadAlternatives represents the alternatives of the selector's regex
+	// matchers which don't return a result value on their own, as equality
+	// matchers, e.g. job="worker" for job=~"api|worker". The selector itself
+	// keeps its result value.
+	DeadAlternatives []string
+
+	// Warnings represents the warnings Prometheus returned along with the
+	// selector's probe results, e.g. PromQL annotations or partial responses
+	Warnings []string
+
+	// PartialResponse reports whether any of Warnings tells the probe result
+	// may be incomplete, see IsPartialResponse
+	PartialResponse bool
+}
+
+// addWarnings records the given query warnings of the selector's probes.
+func (r *SelectorResult) addWarnings(warnings []string) {
+	for _, w := range warnings {
+		if slices.Contains(r.Warnings, w) {
+			continue
+		}
+		r.Warnings = append(r.Warnings, w)
+		r.PartialResponse = r.PartialResponse || IsPartialResponse(w)
+	}
 }
 
 // NewPrometheusRulesChecker returns PrometheusRulesChecker.
@@ -107,19 +383,67 @@ func NewPrometheusRulesChecker(config PrometheusRulesCheckerConfig, client prome
 	if err != nil {
 		return nil, fmt.Errorf("invalid ignore-group pattern: %w", err)
 	}
+	seriesThresholds, err := parseSeriesThresholds(config.MaxSeriesSelectors)
+	if err != nil {
+		return nil, fmt.Errorf("invalid max-series-selector threshold: %w", err)
+	}
 	var sem chan struct{}
 	if config.MaxConcurrency > 0 {
 		sem = make(chan struct{}, config.MaxConcurrency)
 	}
+	retry := newRetrier(config.Retries, config.RetryBackoff)
+	limit := newRateLimiter(config.QPS, config.Burst, config.AdaptiveQPS)
+	var opts []prometheusv1.Option
+	if config.QueryTimeout > 0 {
+		opts = append(opts, prometheusv1.WithTimeout(config.QueryTimeout))
+	}
+	probe := newPrometheusProbe(
+		config.PrometheusURL,
+		client,
+		retry,
+		limit,
+		opts...,
+	)
+	var prober Prober = probe
+	switch config.Prober {
+	case "", ProberQuery:
+		if config.BatchSize > 1 {
+			prober = &batchProbe{prometheusProbe: probe, size: config.BatchSize}
+		}
+	case ProberSeries:
+		if config.BatchSize > 1 {
+			return nil, fmt.Errorf("batch probes require the %q prober", ProberQuery)
+		}
+		if config.SeriesLimit > 0 {
+			opts = append(opts, prometheusv1.WithLimit(config.SeriesLimit))
+		}
+		prober = newSeriesProbe(client, retry, limit, opts...)
+	default:
+		return nil, fmt.Errorf("unknown prober %q", config.Prober)
+	}
 	return &PrometheusRulesChecker{
-		probe: newPrometheusProbe(
-			config.PrometheusURL,
-			client,
-		),
+		probe:                  prober,
+		query:                  probe,
 		parser:                 promql.NewParser(promql.Options{}),
 		ignoredSelectorsRegexp: ignoredSelectors,
 		ignoredGroupsRegexp:    ignoredGroups,
+		drillDown:              config.DrillDown,
+		suggestions:            config.Suggestions,
+		alternations:           config.Alternations,
+		groupingLabels:         config.GroupingLabels,
+		joins:                  config.Joins,
+		recordingRules:         config.RecordingRules,
+		evaluate:               config.Evaluate,
+		backtest:               config.Backtest,
+		backtestStep:           config.BacktestStep,
+		lookback:               config.Lookback,
+		maxAge:                 config.MaxAge,
+		maxSeries:              config.MaxSeries,
+		seriesThresholds:       seriesThresholds,
+		continueOnError:        config.ContinueOnError,
 		sem:                    sem,
+		retry:                  retry,
+		limit:                  limit,
 	}, nil
 }
 
@@ -150,27 +474,46 @@ func (prc *PrometheusRulesChecker) CheckRuleGroup(ctx context.Context, group Rul
 
 	// Compute the evaluation timestamp once per group so all of its selectors
 	// are probed at a consistent point in time, honoring the group's
-	// query_offset (if any) instead of always probing "now".
+	// query_offset (if any) instead of always probing "now". Within a run,
+	// all groups share the run's timestamp so their probes can be cached.
 	ts := time.Now()
+	if run := prc.currentRun(); run != nil {
+		ts = run.ts
+	}
 	if group.QueryOffset > 0 {
 		ts = ts.Add(-group.QueryOffset)
 	}
 
+	// rules which can't be probed before the deadline are reported as unchecked below
+	if err := prc.prefetchBatches(ctx, ts, group.Rules); err != nil && !deadlineExceeded(ctx) {
+		return nil, err
+	}
+
 	for _, rule := range group.Rules {
 		eg.Go(func() error {
-			success, failed, err := prc.probeSelectorResults(ctx, ts, rule.Expression)
-			if err != nil {
+			checked, err := prc.checkRule(ctx, ts, rule)
+			switch {
+			case err == nil:
+			case deadlineExceeded(ctx):
+				checked = CheckResult{Unchecked: true}
+			case !prc.continueOnError || ctx.Err() != nil:
+				// a canceled run can't be continued, whatever the mode
 				return fmt.Errorf("rule %q: %w", rule.Name, err)
+			default:
+				checked = CheckResult{Error: err}
 			}
+			checked.File = group.File
+			checked.Group = group.Name
+			checked.Name = rule.Name
+			checked.Expression = rule.Expression
+			checked.Interval = group.Interval
+			checked.Alerting = rule.Alerting
+			checked.For = rule.For
+			checked.KeepFiringFor = rule.KeepFiringFor
+			checked.Labels = rule.Labels
+			checked.Annotations = rule.Annotations
 			mu.Lock()
-			results = append(results, CheckResult{
-				File:       group.File,
-				Name:       rule.Name,
-				Group:      group.Name,
-				Expression: rule.Expression,
-				Results:    success,
-				NoResults:  failed,
-			})
+			results = append(results, checked)
 			mu.Unlock()
 			return nil
 		})
@@ -181,6 +524,222 @@ func (prc *PrometheusRulesChecker) CheckRuleGroup(ctx context.Context, group Rul
 	return results, nil
 }
 
+// deadlineExceeded reports whether the deadline of ctx was exceeded.
+func deadlineExceeded(ctx context.Context) bool {
+	return errors.Is(ctx.Err(), context.DeadlineExceeded)
+}
+
+// checkRule probes the selectors of a single rule at the evaluation timestamp ts
+// and runs all enabled follow-up analyses on them.
+// checkRule returns a CheckResult holding the rule's selector results only.
+func (prc *PrometheusRulesChecker) checkRule(ctx context.Context, ts time.Time, rule Rule) (CheckResult, error) {
+	suppressions, err := rule.suppressions()
+	if err != nil {
+		return CheckResult{}, fmt.Errorf("suppression: %w", err)
+	}
+	c, err := prc.probeRule(ctx, ts, rule)
+	if err != nil {
+		return CheckResult{}, err
+	}
+	c.suppress(suppressions)
+	if err := prc.runAlternations(ctx, c); err != nil {
+		return CheckResult{}, fmt.Errorf("alternations: %w", err)
+	}
+	if err := prc.runGroupingLabels(ctx, c); err != nil {
+		return CheckResult{}, fmt.Errorf("grouping labels: %w", err)
+	}
+	if err := prc.runLastSeen(ctx, c); err != nil {
+		return CheckResult{}, fmt.Errorf("last seen: %w", err)
+	}
+	if err := prc.runDependencies(ctx, c); err != nil {
+		return CheckResult{}, fmt.Errorf("recording rules: %w", err)
+	}
+	if err := prc.runDrillDown(ctx, c); err != nil {
+		return CheckResult{}, fmt.Errorf("drill-down: %w", err)
+	}
+	if err := prc.runJoins(ctx, c); err != nil {
+		return CheckResult{}, fmt.Errorf("joins: %w", err)
+	}
+	if err := prc.runEvaluate(ctx, c); err != nil {
+		return CheckResult{}, fmt.Errorf("evaluate: %w", err)
+	}
+	if err := prc.runBacktest(ctx, c); err != nil {
+		return CheckResult{}, fmt.Errorf("backtest: %w", err)
+	}
+	return c.checkResult(), nil
+}
+
+// ruleCheck represents the state of checking a single rule, handed from one
+// analysis of checkRule to the next.
+type ruleCheck struct {
+	rule Rule
+	ts   time.Time
+
+	// selectors collects the findings per selector
+	selectors *selectorResults
+
+	// success, failed, expectedEmpty and notRecorded partition the rule's
+	// probed selectors, suppressed represents the failed ones whose findings
+	// are suppressed by the rule
+	success       []ruleSelector
+	failed        []ruleSelector
+	expectedEmpty []ruleSelector
+	notRecorded   []ruleSelector
+	suppressed    []SuppressedSelector
+
+	// result holds the rule-level findings
+	result CheckResult
+}
+
+// probeRule probes the selectors of rule at the evaluation timestamp ts,
+// setting the selectors within absent() and unless apart, and probes the
+// ones without a result value again over the lookback window, if configured.
+func (prc *PrometheusRulesChecker) probeRule(ctx context.Context, ts time.Time, rule Rule) (*ruleCheck, error) {
+	ruleSelectors, err := getRuleSelectors(prc.parser, rule.Expression)
+	if err != nil {
+		return nil, fmt.Errorf("selectors: %w", err)
+	}
+	c := &ruleCheck{rule: rule, ts: ts, selectors: newSelectorResults()}
+	c.success, c.failed, err = prc.probeSelectors(ctx, ts, ruleSelectors, c.selectors)
+	if err != nil {
+		return nil, err
+	}
+	c.expectedEmpty, c.failed = partitionExpectedEmpty(c.failed)
+	if prc.lookback > 0 {
+		c.success, c.failed, err = prc.probeLookback(ctx, ts, c.success, c.failed, c.selectors)
+		if err != nil {
+			return nil, fmt.Errorf("lookback: %w", err)
+		}
+	}
+	return c, nil
+}
+
+// suppress sets the selectors without a result value whose findings are
+// suppressed by the given active suppressions apart.
+func (c *ruleCheck) suppress(suppressions []Suppression) {
+	c.suppressed, c.failed = partitionSuppressed(c.failed, suppressions, c.ts)
+}
+
+// runAlternations probes the alternatives of the regex matchers of the selectors with a result value, if enabled.
+func (prc *PrometheusRulesChecker) runAlternations(ctx context.Context, c *ruleCheck) error {
+	if !prc.alternations || len(c.success) == 0 {
+		return nil
+	}
+	return prc.probeAlternatives(ctx, c.ts, c.success, c.selectors)
+}
+
+// runGroupingLabels probes the selectors with a result value for the labels of their grouping and matching clauses, if enabled.
+func (prc *PrometheusRulesChecker) runGroupingLabels(ctx context.Context, c *ruleCheck) error {
+	if !prc.groupingLabels || len(c.success) == 0 {
+		return nil
+	}
+	return prc.probeGroupingLabels(ctx, c.ts, c.success, c.selectors)
+}
+
+// runLastSeen probes the newest sample of the selectors, if a max age is configured.
+func (prc *PrometheusRulesChecker) runLastSeen(ctx context.Context, c *ruleCheck) error {
+	if prc.maxAge <= 0 || prc.query == nil {
+		return nil
+	}
+	return prc.probeLastSeen(ctx, c.ts, slices.Concat(c.success, c.failed), c.selectors)
+}
+
+// runDependencies checks the source selectors of the run's recording rules
+// the selectors without a result value refer to, if enabled.
+func (prc *PrometheusRulesChecker) runDependencies(ctx context.Context, c *ruleCheck) error {
+	if !prc.recordingRules || len(c.failed) == 0 {
+		return nil
+	}
+	var err error
+	c.notRecorded, c.failed, err = prc.resolveRecordingRules(ctx, c.ts, c.failed, c.selectors)
+	return err
+}
+
+// runDrillDown drills the selectors without a result value down to their culprit matchers, if enabled.
+func (prc *PrometheusRulesChecker) runDrillDown(ctx context.Context, c *ruleCheck) error {
+	if !prc.drillDown || len(c.failed) == 0 {
+		return nil
+	}
+	return prc.drillDownSelectors(ctx, c.ts, c.failed, c.selectors)
+}
+
+// runJoins evaluates the operands of the rule's binary operations with vector matching, if enabled.
+func (prc *PrometheusRulesChecker) runJoins(ctx context.Context, c *ruleCheck) error {
+	if !prc.joins || prc.query == nil {
+		return nil
+	}
+	var err error
+	c.result.EmptyJoins, err = prc.probeJoins(ctx, c.ts, c.rule.Expression)
+	return err
+}
+
+// runEvaluate evaluates the rule's whole expression, if enabled. It must run
+// after all analyses which find selectors or joins breaking the expression.
+func (prc *PrometheusRulesChecker) runEvaluate(ctx context.Context, c *ruleCheck) error {
+	if !prc.evaluate || prc.query == nil {
+		return nil
+	}
+	broken := len(c.failed) > 0 || len(c.suppressed) > 0 || len(c.result.EmptyJoins) > 0
+	var err error
+	c.result.Status, c.result.Series, err = prc.evaluateRule(ctx, c.ts, c.rule.Expression, broken)
+	return err
+}
+
+// runBacktest backtests the rule over historical data, if enabled and the rule is an alerting rule.
+func (prc *PrometheusRulesChecker) runBacktest(ctx context.Context, c *ruleCheck) error {
+	if prc.backtest <= 0 || !c.rule.Alerting || prc.query == nil {
+		return nil
+	}
+	var err error
+	c.result.Backtest, err = prc.backtestRule(ctx, c.ts, c.rule)
+	return err
+}
+
+// checkResult returns the CheckResult of the rule's selectors and rule-level findings.
+func (c *ruleCheck) checkResult() CheckResult {
+	result := c.result
+	result.Results = selectorTexts(slices.Concat(c.success, c.notRecorded))
+	result.NoResults = selectorTexts(c.failed)
+	result.ExpectedEmpty = selectorTexts(c.expectedEmpty)
+	result.Suppressed = c.suppressed
+	result.Selectors = c.selectors.list()
+	return result
+}
+
+// selectorResults collects SelectorResult values by selector, preserving the
+// order selectors were first seen in.
+type selectorResults struct {
+	order      []string
+	bySelector map[string]*SelectorResult
+}
+
+func newSelectorResults() *selectorResults {
+	return &selectorResults{bySelector: map[string]*SelectorResult{}}
+}
+
+// get returns the SelectorResult for selector, adding it if necessary.
+func (s *selectorResults) get(selector string) *SelectorResult {
+	if r, ok := s.bySelector[selector]; ok {
+		return r
+	}
+	r := &SelectorResult{Selector: selector}
+	s.bySelector[selector] = r
+	s.order = append(s.order, selector)
+	return r
+}
+
+// list returns the collected SelectorResult values, nil if there are none.
+func (s *selectorResults) list() []SelectorResult {
+	if len(s.order) == 0 {
+		return nil
+	}
+	out := make([]SelectorResult, 0, len(s.order))
+	for _, selector := range s.order {
+		out = append(out, *s.bySelector[selector])
+	}
+	return out
+}
+
 // IsIgnoredGroup reports whether the given group name matches any of the
 // configured IgnoredGroupsRegexp patterns.
 func (prc *PrometheusRulesChecker) IsIgnoredGroup(name string) bool {
@@ -201,21 +760,12 @@ func isIgnored(patterns []*regexp.Regexp, s string) bool {
 	return slices.ContainsFunc(patterns, func(re *regexp.Regexp) bool { return re.MatchString(s) })
 }
 
-// probeSelectorResults probes the given PromQL expression string for selectors without a result value.
-// probeSelectorResults probes at the given evaluation timestamp ts.
-// probeSelectorResults returns a list of successful selectors and failed selectors.
-func (prc *PrometheusRulesChecker) probeSelectorResults(ctx context.Context, ts time.Time, promqlExpression string) ([]string, []string, error) {
-	selectorsWithoutResult := []string{}
-	selectorsWithResult := []string{}
-
-	selectors, err := getVectorSelectors(prc.parser, promqlExpression)
-	if err != nil {
-		return selectorsWithResult, selectorsWithoutResult, fmt.Errorf("getVectorSelectors failed: %w", err)
-	}
-
-	if len(selectors) == 0 {
-		return selectorsWithResult, selectorsWithoutResult, nil
-	}
+// probeSelectors probes the given selectors of a rule evaluated at timestamp ts, skipping ignored ones,
+// and records each probed selector's series count, cardinality threshold and query warnings in results.
+// probeSelectors returns a list of successful selectors and failed selectors.
+func (prc *PrometheusRulesChecker) probeSelectors(ctx context.Context, ts time.Time, selectors []ruleSelector, results *selectorResults) ([]ruleSelector, []ruleSelector, error) {
+	selectorsWithoutResult := []ruleSelector{}
+	selectorsWithResult := []ruleSelector{}
 
 	for _, selector := range selectors {
 		if err := ctx.Err(); err != nil {
@@ -223,31 +773,21 @@ func (prc *PrometheusRulesChecker) probeSelectorResults(ctx context.Context, ts
 		}
 
 		// we can move on if this selector is ignored
-		if isIgnoredSelector(prc.ignoredSelectorsRegexp, selector) {
-			continue
-		}
-
-		matchers, err := prc.parser.ParseMetricSelector(selector)
+		skip, err := prc.skipSelector(selector)
 		if err != nil {
 			return selectorsWithResult, selectorsWithoutResult, err
 		}
-		if ignoreMatchers(matchers) {
+		if skip {
 			continue
 		}
-		if prc.sem != nil {
-			select {
-			case prc.sem <- struct{}{}:
-			case <-ctx.Done():
-				return selectorsWithResult, selectorsWithoutResult, ctx.Err()
-			}
-		}
-		val, err := prc.probe.ProbeSelector(ctx, selector, ts)
-		if prc.sem != nil {
-			<-prc.sem
-		}
+		probeCtx, warnings := withWarnings(ctx)
+		val, err := prc.probeSelector(probeCtx, selector, ts)
 		if err != nil {
 			return selectorsWithResult, selectorsWithoutResult, err
 		}
+		r := results.get(selector.text)
+		r.addWarnings(warnings.list())
+		prc.recordSeries(r, selector, val)
 		if val < 1 {
 			selectorsWithoutResult = append(selectorsWithoutResult, selector)
 		} else {
@@ -257,35 +797,83 @@ func (prc *PrometheusRulesChecker) probeSelectorResults(ctx context.Context, ts
 	return selectorsWithResult, selectorsWithoutResult, nil
 }
 
+// skipSelector reports whether the given selector is ignored and must not be probed.
+func (prc *PrometheusRulesChecker) skipSelector(selector ruleSelector) (bool, error) {
+	if isIgnoredSelector(prc.ignoredSelectorsRegexp, selector.expr) {
+		return true, nil
+	}
+	matchers, err := prc.parser.ParseMetricSelector(selector.expr)
+	if err != nil {
+		return false, err
+	}
+	return ignoreMatchers(matchers), nil
+}
+
+// probeSelector probes a single selector the way the rule evaluates it at the
+// evaluation timestamp ts: selectors with a range (or within a subquery) are
+// probed for any sample in their window, and offset and @ modifiers shift
+// the probed timestamp. probeSelector honors the configured probe concurrency
+// bound and the run's probe cache.
+func (prc *PrometheusRulesChecker) probeSelector(ctx context.Context, selector ruleSelector, ts time.Time) (float64, error) {
+	if selector.window > 0 {
+		return prc.probeSelectorRange(ctx, selector.expr, selector.evalTime(ts), selector.window)
+	}
+	evalTime := selector.evalTime(ts)
+	return prc.cachedProbe(ctx, selector.expr, evalTime, 0, func(ctx context.Context) (float64, error) {
+		if err := prc.acquire(ctx); err != nil {
+			return 0, err
+		}
+		defer prc.release()
+		return prc.probe.ProbeSelector(ctx, selector.expr, evalTime)
+	})
+}
+
+// acquire blocks until a probe slot is available or ctx is done.
+// Every successful acquire must be paired with a release.
+func (prc *PrometheusRulesChecker) acquire(ctx context.Context) error {
+	if prc.sem == nil {
+		return nil
+	}
+	select {
+	case prc.sem <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release frees a probe slot taken by acquire.
+func (prc *PrometheusRulesChecker) release() {
+	if prc.sem != nil {
+		<-prc.sem
+	}
+}
+
 // visit is a helper struct to traverse a PromQL expression's abstract syntax tree.
 type visit struct {
-	vectorSelectors []string
+	vectorSelectors []ruleSelector
 }
 
 // Visit implements Visitor interface.
-func (v *visit) Visit(node promql.Node, _ []promql.Node) (promql.Visitor, error) {
+func (v *visit) Visit(node promql.Node, path []promql.Node) (promql.Visitor, error) {
 	if node == nil {
 		return v, nil
 	}
 	switch n := node.(type) {
 	case *promql.VectorSelector:
-		vs := promql.VectorSelector{
-			Name:          n.Name,
-			LabelMatchers: n.LabelMatchers,
-		}
-		v.vectorSelectors = append(v.vectorSelectors, vs.String())
+		v.vectorSelectors = append(v.vectorSelectors, newRuleSelector(n, path))
 	}
 	return v, nil
 }
 
-// getVectorSelectors returns a list of vectorSelectors parsed from the given query.
-func getVectorSelectors(p promql.Parser, promqlExpression string) ([]string, error) {
+// getRuleSelectors returns the vector selectors parsed from the given query, along with their modifiers.
+func getRuleSelectors(p promql.Parser, promqlExpression string) ([]ruleSelector, error) {
 	expr, err := p.ParseExpr(promqlExpression)
 	if err != nil {
 		return nil, fmt.Errorf("promql parse error: %w", err)
 	}
 	v := &visit{
-		vectorSelectors: make([]string, 0),
+		vectorSelectors: make([]ruleSelector, 0),
 	}
 	_ = promql.Walk(v, expr, nil)
 	return v.vectorSelectors, nil
@@ -23,7 +23,7 @@ func mustCompileAll(patterns []string) []*regexp.Regexp {
 	return out
 }
 
-func Test_getVectorSelectors(t *testing.T) {
+func Test_getRuleSelectors(t *testing.T) {
 	type args struct {
 		promqlExpression string
 	}
@@ -68,13 +68,17 @@ func Test_getVectorSelectors(t *testing.T) {
 	p := promql.NewParser(promql.Options{})
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := getVectorSelectors(p, tt.args.promqlExpression)
+			selectors, err := getRuleSelectors(p, tt.args.promqlExpression)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("getVectorSelectors() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("getRuleSelectors() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			got := make([]string, 0, len(selectors))
+			for _, s := range selectors {
+				got = append(got, s.expr)
+			}
 			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("getVectorSelectors() got = %v, want %v", got, tt.want)
+				t.Errorf("getRuleSelectors() got = %v, want %v", got, tt.want)
 			}
 		})
 	}
@@ -246,6 +250,12 @@ type fakeProber struct {
 	// tsCalls records the evaluation timestamp passed to ProbeSelector for
 	// each call, in the same order as calls
 	tsCalls []time.Time
+	// rangeValues maps a selector string to the value ProbeSelectorRange returns
+	rangeValues map[string]float64
+	// windows records the window passed to ProbeSelectorRange for each selector
+	windows map[string]time.Duration
+	// rangeTs records the evaluation timestamp passed to ProbeSelectorRange for each selector
+	rangeTs map[string]time.Time
 }
 
 func (f *fakeProber) ProbeSelector(ctx context.Context, selector string, ts time.Time) (float64, error) {
@@ -260,17 +270,35 @@ func (f *fakeProber) ProbeSelector(ctx context.Context, selector string, ts time
 	return f.values[selector], nil
 }
 
-func TestProbeSelectorResults_ContinuesAfterIgnoredMatcher(t *testing.T) {
+func (f *fakeProber) ProbeSelectorRange(ctx context.Context, selector string, ts time.Time, window time.Duration) (float64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	if f.windows == nil {
+		f.windows = map[string]time.Duration{}
+		f.rangeTs = map[string]time.Time{}
+	}
+	f.windows[selector] = window
+	f.rangeTs[selector] = ts
+	if f.err != nil {
+		return 0, f.err
+	}
+	return f.rangeValues[selector], nil
+}
+
+func TestProbeSelectors_ContinuesAfterIgnoredMatcher(t *testing.T) {
 	fp := &fakeProber{values: map[string]float64{
 		`up{job="x"}`: 1, // has a result
 	}}
 	prc := &PrometheusRulesChecker{probe: fp, parser: promql.NewParser(promql.Options{})}
 
 	// ALERTS{...} is ignored; up{job="x"} must still be probed.
-	success, failed, err := prc.probeSelectorResults(t.Context(), time.Now(), `ALERTS{alertname="Foo"} or up{job="x"}`)
+	selectors, err := getRuleSelectors(prc.parser, `ALERTS{alertname="Foo"} or up{job="x"}`)
+	require.NoError(t, err)
+	success, failed, err := prc.probeSelectors(t.Context(), time.Now(), selectors, newSelectorResults())
 	require.NoError(t, err)
 	require.Contains(t, fp.calls, `up{job="x"}`, "selector after the ignored ALERTS selector must still be probed")
-	require.Equal(t, []string{`up{job="x"}`}, success)
+	require.Equal(t, []string{`up{job="x"}`}, selectorTexts(success))
 	require.Empty(t, failed)
 }
 
@@ -285,6 +313,76 @@ func TestCheckRuleGroup_PropagatesProbeError(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestCheckRuleGroup_ContinueOnErrorRecordsRuleErrors(t *testing.T) {
+	fp := &fakeProber{values: map[string]float64{`up{job="x"}`: 1}}
+	prc := &PrometheusRulesChecker{probe: fp, parser: promql.NewParser(promql.Options{}), continueOnError: true}
+	group := RuleGroup{
+		Name: "g",
+		Rules: []Rule{
+			{Name: "ok", Expression: `up{job="x"}`},
+			{Name: "broken", Expression: `sum(up{job="x"}`},
+		},
+	}
+	results, err := prc.CheckRuleGroup(t.Context(), group)
+	require.NoError(t, err, "a rule error must not abort the group")
+	require.Len(t, results, 2)
+
+	byName := map[string]CheckResult{}
+	for _, r := range results {
+		byName[r.Name] = r
+	}
+	require.NoError(t, byName["ok"].Error)
+	require.Equal(t, []string{`up{job="x"}`}, byName["ok"].Results)
+
+	broken := byName["broken"]
+	require.Error(t, broken.Error)
+	require.Equal(t, "g", broken.Group)
+	require.Equal(t, `sum(up{job="x"}`, broken.Expression)
+	require.Empty(t, broken.Results)
+	require.Empty(t, broken.NoResults)
+}
+
+func TestCheckRuleGroup_ContinueOnErrorStillAbortsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+	fp := &fakeProber{}
+	prc := &PrometheusRulesChecker{probe: fp, parser: promql.NewParser(promql.Options{}), continueOnError: true}
+	group := RuleGroup{
+		Name:  "g",
+		Rules: []Rule{{Name: "r", Expression: `up{job="x"}`}},
+	}
+	_, err := prc.CheckRuleGroup(ctx, group)
+	require.ErrorIs(t, err, context.Canceled)
+}
+
+func TestCheckRuleGroup_CarriesRuleMetadata(t *testing.T) {
+	fp := &fakeProber{values: map[string]float64{`up{job="x"}`: 1}}
+	prc := &PrometheusRulesChecker{probe: fp, parser: promql.NewParser(promql.Options{})}
+	group := RuleGroup{
+		Name:     "g",
+		Interval: 30 * time.Second,
+		Rules: []Rule{{
+			Name:          "InstanceDown",
+			Expression:    `up{job="x"} == 0`,
+			Alerting:      true,
+			For:           5 * time.Minute,
+			KeepFiringFor: time.Minute,
+			Labels:        map[string]string{"severity": "critical"},
+			Annotations:   map[string]string{"summary": "instance down"},
+		}},
+	}
+	results, err := prc.CheckRuleGroup(t.Context(), group)
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	got := results[0]
+	require.Equal(t, 30*time.Second, got.Interval)
+	require.True(t, got.Alerting)
+	require.Equal(t, 5*time.Minute, got.For)
+	require.Equal(t, time.Minute, got.KeepFiringFor)
+	require.Equal(t, map[string]string{"severity": "critical"}, got.Labels)
+	require.Equal(t, map[string]string{"summary": "instance down"}, got.Annotations)
+}
+
 func TestCheckRuleGroup_AppliesQueryOffset(t *testing.T) {
 	fp := &fakeProber{values: map[string]float64{`up{job="x"}`: 1}}
 	prc := &PrometheusRulesChecker{probe: fp, parser: promql.NewParser(promql.Options{})}
@@ -353,13 +451,15 @@ func TestProbeSelector_HonorsContextCancellation(t *testing.T) {
 	prc := &PrometheusRulesChecker{probe: fp, parser: promql.NewParser(promql.Options{})}
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel()
-	_, _, err := prc.probeSelectorResults(ctx, time.Now(), `up{job="x"}`)
+	selectors, err := getRuleSelectors(prc.parser, `up{job="x"}`)
+	require.NoError(t, err)
+	_, _, err = prc.probeSelectors(ctx, time.Now(), selectors, newSelectorResults())
 	require.ErrorIs(t, err, context.Canceled)
 }
 
 // siblingCancelProber lets a test prove that an error from one rule's probe
 // cancels the derived context used by sibling probes in the same group.
-// probeSelectorResults checks ctx.Err() at the top of its selector loop,
+// probeSelectors checks ctx.Err() at the top of its selector loop,
 // before calling ProbeSelector, so if the erroring probe cancels ctx before
 // the sibling goroutine reaches ProbeSelector, that goroutine would
 // short-circuit at the loop guard and never observe cancellation inside
@@ -429,13 +529,35 @@ func TestCheckRuleGroup_CancelsSiblingsOnError(t *testing.T) {
 	}
 }
 
-func TestGetVectorSelectors_UTF8Names(t *testing.T) {
+func TestGetRuleSelectors_UTF8Names(t *testing.T) {
 	p := promql.NewParser(promql.Options{})
 	// dotted metric and label names require the quoted brace form
-	sel, err := getVectorSelectors(p, `sum({"http.server.duration", "http.route"="/x"})`)
+	sel, err := getRuleSelectors(p, `sum({"http.server.duration", "http.route"="/x"})`)
 	require.NoError(t, err)
 	require.Len(t, sel, 1)
 	// the reconstructed selector must be re-parseable
-	_, err = p.ParseMetricSelector(sel[0])
-	require.NoError(t, err, "reconstructed UTF-8 selector must round-trip: %q", sel[0])
+	_, err = p.ParseMetricSelector(sel[0].expr)
+	require.NoError(t, err, "reconstructed UTF-8 selector must round-trip: %q", sel[0].expr)
+}
+
+func TestCheckRuleGroup_ReportsUncheckedRulesOnDeadline(t *testing.T) {
+	ctx, cancel := context.WithDeadline(t.Context(), time.Now().Add(-time.Second))
+	defer cancel()
+	fp := &fakeProber{values: map[string]float64{`up{job="x"}`: 1}}
+	prc := &PrometheusRulesChecker{probe: fp, parser: promql.NewParser(promql.Options{})}
+	group := RuleGroup{
+		Name: "g",
+		Rules: []Rule{
+			{Name: "r1", Expression: `up{job="x"}`},
+			{Name: "r2", Expression: `sum(up{job="x"})`},
+		},
+	}
+	results, err := prc.CheckRuleGroup(ctx, group)
+	require.NoError(t, err, "an exceeded deadline must be reported per rule")
+	require.Len(t, results, 2)
+	for _, r := range results {
+		require.True(t, r.Unchecked, "rule %s must be reported as unchecked", r.Name)
+		require.Equal(t, "g", r.Group)
+		require.Empty(t, r.Results)
+	}
 }
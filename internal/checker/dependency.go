@@ -0,0 +1,113 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// maxDependencyDepth bounds how many recording rules deep source selectors are resolved.
+const maxDependencyDepth = 8
+
+// recordingRules represents the recording rules of a run by the name of the metric they record.
+type recordingRules map[string][]Rule
+
+// newRecordingRules returns the recording rules of the given groups.
+func newRecordingRules(groups []RuleGroup) recordingRules {
+	rules := recordingRules{}
+	for _, group := range groups {
+		for _, rule := range group.Rules {
+			if !rule.Alerting {
+				rules[rule.Name] = append(rules[rule.Name], rule)
+			}
+		}
+	}
+	return rules
+}
+
+// resolveRecordingRules checks the source selectors of the run's recording
+// rules the given selectors without a result value refer to. Selectors whose
+// recording rules' sources all return a result value are returned as not
+// recorded yet, the others are returned as failed, with the chains down to
+// their sources without a result value recorded in results.
+func (prc *PrometheusRulesChecker) resolveRecordingRules(ctx context.Context, ts time.Time, failed []ruleSelector, results *selectorResults) (notRecorded, stillFailed []ruleSelector, err error) {
+	run := prc.currentRun()
+	if run == nil || len(run.recordings) == 0 {
+		return nil, failed, nil
+	}
+	for _, s := range failed {
+		name := prc.metricName(s)
+		if _, ok := run.recordings[name]; !ok {
+			stillFailed = append(stillFailed, s)
+			continue
+		}
+		chains, err := prc.sourceChains(ctx, s.evalTime(ts), name, run.recordings, nil)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(chains) == 0 {
+			results.get(s.text).NotRecorded = true
+			notRecorded = append(notRecorded, s)
+			continue
+		}
+		results.get(s.text).DependencyChains = chains
+		stillFailed = append(stillFailed, s)
+	}
+	return notRecorded, stillFailed, nil
+}
+
+// sourceChains probes the source selectors of the recording rules recording
+// name at ts, returning a chain per source selector without a result value,
+// e.g. [job:foo:rate5m foo_total]. Sources which are recording rules
+// themselves are resolved in turn, visited holds the names resolved so far.
+// Recording rules depending on themselves, or nested too deep, end their
+// chain as they can't be resolved.
+func (prc *PrometheusRulesChecker) sourceChains(ctx context.Context, ts time.Time, name string, rules recordingRules, visited []string) ([][]string, error) {
+	if slices.Contains(visited, name) || len(visited) >= maxDependencyDepth {
+		return [][]string{{name}}, nil
+	}
+	visited = append(visited, name)
+
+	var chains [][]string
+	for _, rule := range rules[name] {
+		sources, err := getRuleSelectors(prc.parser, rule.Expression)
+		if err != nil {
+			return nil, fmt.Errorf("recording rule %q: %w", name, err)
+		}
+		_, failed, err := prc.probeSelectors(ctx, ts, sources, newSelectorResults())
+		if err != nil {
+			return nil, err
+		}
+		_, failed = partitionExpectedEmpty(failed)
+		for _, source := range failed {
+			sourceName := prc.metricName(source)
+			if _, ok := rules[sourceName]; !ok {
+				chains = append(chains, []string{name, source.text})
+				continue
+			}
+			sub, err := prc.sourceChains(ctx, source.evalTime(ts), sourceName, rules, visited)
+			if err != nil {
+				return nil, err
+			}
+			for _, chain := range sub {
+				chains = append(chains, append([]string{name}, chain...))
+			}
+		}
+	}
+	return chains, nil
+}
+
+// metricName returns the metric name the selector selects, empty if it
+// doesn't select a single metric name.
+func (prc *PrometheusRulesChecker) metricName(s ruleSelector) string {
+	matchers, err := prc.parser.ParseMetricSelector(s.expr)
+	if err != nil {
+		return ""
+	}
+	name, _ := splitNameMatcher(matchers)
+	if name == nil {
+		return ""
+	}
+	return name.Value
+}
@@ -0,0 +1,54 @@
+package checker
+
+import (
+	"testing"
+
+	promql "github.com/prometheus/prometheus/promql/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckRule_ResolvesRecordingRules(t *testing.T) {
+	fp := &fakeProber{values: map[string]float64{`up`: 1}, rangeValues: map[string]float64{`foo_total`: 1}}
+	prc := &PrometheusRulesChecker{probe: fp, parser: promql.NewParser(promql.Options{}), recordingRules: true}
+	prc.StartRun(
+		RuleGroup{Name: "records", Rules: []Rule{
+			{Name: "job:foo:rate5m", Expression: `sum by (job) (rate(foo_total[5m]))`},
+			{Name: "job:bar:rate5m", Expression: `sum by (job) (rate(bar_total[5m])) and up`},
+			{Name: "job:bar:ratio", Expression: `job:bar:rate5m / job:foo:rate5m`},
+			{Name: "loop", Expression: `loop`},
+		}},
+		RuleGroup{Name: "alerts", Rules: []Rule{
+			{Name: "FooAlert", Alerting: true, Expression: `job:foo:rate5m > 0`},
+		}},
+	)
+	ts := prc.currentRun().ts
+
+	got, err := prc.checkRule(t.Context(), ts, Rule{Name: "A", Alerting: true, Expression: `job:foo:rate5m{job="api"} > 0 and job:bar:ratio > 1 and FooAlert and loop`})
+	require.NoError(t, err)
+	require.Equal(t, []string{`job:foo:rate5m{job="api"}`}, got.Results)
+	require.Equal(t, []string{`job:bar:ratio`, `FooAlert`, `loop`}, got.NoResults, "alerting rules record no metric")
+
+	require.Len(t, got.Selectors, 4)
+	require.Equal(t, SelectorResult{Selector: `job:foo:rate5m{job="api"}`, NotRecorded: true}, got.Selectors[0])
+	require.Equal(t, SelectorResult{
+		Selector:         `job:bar:ratio`,
+		DependencyChains: [][]string{{"job:bar:ratio", "job:bar:rate5m", "bar_total[5m]"}},
+	}, got.Selectors[1])
+	require.Equal(t, SelectorResult{Selector: `FooAlert`}, got.Selectors[2])
+	require.Equal(t, SelectorResult{Selector: `loop`, DependencyChains: [][]string{{"loop", "loop"}}}, got.Selectors[3], "cycles are never resolved")
+}
+
+func TestCheckRule_RecordingRulesDisabled(t *testing.T) {
+	fp := &fakeProber{rangeValues: map[string]float64{`foo_total`: 1}}
+	prc := &PrometheusRulesChecker{probe: fp, parser: promql.NewParser(promql.Options{})}
+	prc.StartRun(RuleGroup{Name: "records", Rules: []Rule{
+		{Name: "job:foo:rate5m", Expression: `sum by (job) (rate(foo_total[5m]))`},
+	}})
+
+	got, err := prc.checkRule(t.Context(), prc.currentRun().ts, Rule{Name: "A", Expression: `job:foo:rate5m > 0`})
+	require.NoError(t, err)
+	require.Equal(t, []string{`job:foo:rate5m`}, got.NoResults)
+	require.Len(t, got.Selectors, 1)
+	require.False(t, got.Selectors[0].NotRecorded)
+	require.Equal(t, []string{`job:foo:rate5m`}, fp.calls)
+}
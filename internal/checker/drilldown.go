@@ -0,0 +1,126 @@
+package checker
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/common/model"
+	"github.com/prometheus/prometheus/model/labels"
+	promql "github.com/prometheus/prometheus/promql/parser"
+)
+
+// Diagnosis represents the outcome of a matcher-level drill-down for a selector without results.
+type Diagnosis struct {
+	// MetricMissing reports whether the bare metric name has no series at all
+	MetricMissing bool
+
+	// Culprits represents the label matchers which, once removed on their own, make the selector return a result value
+	Culprits []string
+
+	// Suggestions represents existing metric names (if the metric is missing)
+	// or label matchers (for culprit matchers) closest to the missing ones
+	Suggestions []string
+}
+
+// drillDownSelectors re-probes each of the given selectors without results with its
+// label matchers removed one at a time, and finally as the bare metric name,
+// to find out which matcher excludes every series.
+// drillDownSelectors records a Diagnosis per selector in results.
+func (prc *PrometheusRulesChecker) drillDownSelectors(ctx context.Context, ts time.Time, selectors []ruleSelector, results *selectorResults) error {
+	for _, selector := range selectors {
+		matchers, err := prc.parser.ParseMetricSelector(selector.expr)
+		if err != nil {
+			return err
+		}
+		diagnosis, err := prc.diagnose(ctx, ts, selector, matchers)
+		if err != nil {
+			return err
+		}
+		if prc.suggestions && prc.query != nil {
+			if err := prc.suggest(ctx, selector.evalTime(ts), matchers, diagnosis); err != nil {
+				return err
+			}
+		}
+		results.get(selector.text).Diagnosis = diagnosis
+	}
+	return nil
+}
+
+// diagnose runs the drill-down probes for a single selector given by its
+// matchers, keeping the selector's modifiers.
+func (prc *PrometheusRulesChecker) diagnose(ctx context.Context, ts time.Time, selector ruleSelector, matchers []*labels.Matcher) (*Diagnosis, error) {
+	name, others := splitNameMatcher(matchers)
+
+	// probed memoizes probes by selector, since removing the last label
+	// matcher yields the bare metric name probed at the end anyway.
+	probed := map[string]float64{}
+	probe := func(ms []*labels.Matcher) (float64, error) {
+		expr := selectorString(ms)
+		if val, ok := probed[expr]; ok {
+			return val, nil
+		}
+		val, err := prc.probeSelector(ctx, selector.withExpr(expr), ts)
+		if err != nil {
+			return 0, err
+		}
+		probed[expr] = val
+		return val, nil
+	}
+
+	diagnosis := &Diagnosis{}
+	for i, m := range others {
+		remaining := make([]*labels.Matcher, 0, len(matchers)-1)
+		if name != nil {
+			remaining = append(remaining, name)
+		}
+		remaining = append(remaining, others[:i]...)
+		remaining = append(remaining, others[i+1:]...)
+		if len(remaining) == 0 {
+			// {job="x"} without a metric name can't be probed without any matcher
+			continue
+		}
+		val, err := probe(remaining)
+		if err != nil {
+			return nil, err
+		}
+		if val > 0 {
+			diagnosis.Culprits = append(diagnosis.Culprits, m.String())
+		}
+	}
+
+	if name != nil {
+		val, err := probe([]*labels.Matcher{name})
+		if err != nil {
+			return nil, err
+		}
+		diagnosis.MetricMissing = val < 1
+	}
+	return diagnosis, nil
+}
+
+// splitNameMatcher splits matchers into the metric name equality matcher (if any) and all other matchers.
+func splitNameMatcher(matchers []*labels.Matcher) (*labels.Matcher, []*labels.Matcher) {
+	var (
+		name   *labels.Matcher
+		others = make([]*labels.Matcher, 0, len(matchers))
+	)
+	for _, m := range matchers {
+		if name == nil && m.Name == labels.MetricName && m.Type == labels.MatchEqual {
+			name = m
+			continue
+		}
+		others = append(others, m)
+	}
+	return name, others
+}
+
+// selectorString renders matchers as a re-parseable PromQL selector string.
+// Metric names which aren't valid legacy names stay inside the braces, so
+// UTF-8 selectors round-trip.
+func selectorString(matchers []*labels.Matcher) string {
+	vs := promql.VectorSelector{LabelMatchers: matchers}
+	if name, _ := splitNameMatcher(matchers); name != nil && model.IsValidLegacyMetricName(name.Value) {
+		vs.Name = name.Value
+	}
+	return vs.String()
+}
@@ -0,0 +1,74 @@
+package checker
+
+import (
+	"testing"
+	"time"
+
+	promql "github.com/prometheus/prometheus/promql/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDrillDown_FindsCulpritMatcher(t *testing.T) {
+	fp := &fakeProber{values: map[string]float64{
+		`kube_pod_info{namespace="default"}`: 3,
+		`kube_pod_info`:                      5,
+	}}
+	prc := &PrometheusRulesChecker{probe: fp, parser: promql.NewParser(promql.Options{})}
+
+	results := newSelectorResults()
+	err := prc.drillDownSelectors(t.Context(), time.Now(), instantSelectors(`kube_pod_info{job="kube-state-metrics",namespace="default"}`), results)
+	require.NoError(t, err)
+	got := results.list()
+	require.Len(t, got, 1)
+	require.Equal(t, `kube_pod_info{job="kube-state-metrics",namespace="default"}`, got[0].Selector)
+	require.Equal(t, &Diagnosis{Culprits: []string{`job="kube-state-metrics"`}}, got[0].Diagnosis)
+}
+
+func TestDrillDown_MetricMissing(t *testing.T) {
+	fp := &fakeProber{values: map[string]float64{}}
+	prc := &PrometheusRulesChecker{probe: fp, parser: promql.NewParser(promql.Options{})}
+
+	results := newSelectorResults()
+	err := prc.drillDownSelectors(t.Context(), time.Now(), instantSelectors(`node_cpu{mode="idle"}`), results)
+	require.NoError(t, err)
+	got := results.list()
+	require.Len(t, got, 1)
+	require.Equal(t, &Diagnosis{MetricMissing: true}, got[0].Diagnosis)
+	// removing the only label matcher is the bare metric name, which must be probed once
+	require.Equal(t, []string{`node_cpu`}, fp.calls)
+}
+
+func TestDrillDown_SelectorWithoutMetricName(t *testing.T) {
+	fp := &fakeProber{values: map[string]float64{`{env="prod"}`: 1}}
+	prc := &PrometheusRulesChecker{probe: fp, parser: promql.NewParser(promql.Options{})}
+
+	results := newSelectorResults()
+	err := prc.drillDownSelectors(t.Context(), time.Now(), instantSelectors(`{env="prod",job="x"}`), results)
+	require.NoError(t, err)
+	got := results.list()
+	require.Equal(t, &Diagnosis{Culprits: []string{`job="x"`}}, got[0].Diagnosis)
+}
+
+func TestCheckRuleGroup_DrillsDownFailedSelectors(t *testing.T) {
+	fp := &fakeProber{values: map[string]float64{`up`: 1}}
+	prc := &PrometheusRulesChecker{probe: fp, parser: promql.NewParser(promql.Options{}), drillDown: true}
+	group := RuleGroup{Name: "g", Rules: []Rule{{Name: "r", Expression: `up{job="x"}`}}}
+
+	got, err := prc.CheckRuleGroup(t.Context(), group)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	require.Equal(t, []SelectorResult{{
+		Selector:  `up{job="x"}`,
+		Diagnosis: &Diagnosis{Culprits: []string{`job="x"`}},
+	}}, got[0].Selectors)
+}
+
+func Test_selectorString_UTF8RoundTrip(t *testing.T) {
+	p := promql.NewParser(promql.Options{})
+	matchers, err := p.ParseMetricSelector(`{"http.server.duration", "http.route"="/x"}`)
+	require.NoError(t, err)
+	name, _ := splitNameMatcher(matchers)
+	_, err = p.ParseMetricSelector(selectorString(matchers[:1]))
+	require.NoError(t, err)
+	require.Equal(t, "http.server.duration", name.Value)
+}
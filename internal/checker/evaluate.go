@@ -0,0 +1,65 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	promql "github.com/prometheus/prometheus/promql/parser"
+)
+
+// RuleStatus represents the outcome of evaluating a rule's whole expression.
+type RuleStatus string
+
+const (
+	// RuleStatusResults means the expression yields series, for an alerting
+	// rule its condition is currently true.
+	RuleStatusResults RuleStatus = "results"
+
+	// RuleStatusEmpty means the expression yields no series although all of
+	// its selectors return a result value and all of its joins match, for an
+	// alerting rule its condition is currently false.
+	RuleStatusEmpty RuleStatus = "empty"
+
+	// RuleStatusBroken means the expression yields no series and some of its
+	// selectors return no result value or some of its joins never match, so
+	// it can't yield series as it is.
+	RuleStatusBroken RuleStatus = "broken"
+)
+
+// evaluateRule evaluates the whole expression of a rule at the evaluation
+// timestamp ts, honoring the configured probe concurrency bound. broken tells
+// whether the selector and join findings of the rule leave it structurally
+// unable to yield series.
+// evaluateRule returns the rule's status and the number of series it yields.
+func (prc *PrometheusRulesChecker) evaluateRule(ctx context.Context, ts time.Time, promqlExpression string, broken bool) (RuleStatus, int, error) {
+	expr, err := prc.parser.ParseExpr(promqlExpression)
+	if err != nil {
+		return "", 0, fmt.Errorf("promql parse error: %w", err)
+	}
+	if err := prc.acquire(ctx); err != nil {
+		return "", 0, err
+	}
+	defer prc.release()
+	val, err := prc.query.Count(ctx, vectorQuery(expr), ts)
+	if err != nil {
+		return "", 0, err
+	}
+	switch {
+	case val >= 1:
+		return RuleStatusResults, int(val), nil
+	case broken:
+		return RuleStatusBroken, 0, nil
+	default:
+		return RuleStatusEmpty, 0, nil
+	}
+}
+
+// vectorQuery renders the given rule expression as a query yielding a vector.
+func vectorQuery(expr promql.Expr) string {
+	if expr.Type() == promql.ValueTypeScalar {
+		// rules turn scalar results into a single series, so does vector()
+		return fmt.Sprintf("vector(%s)", expr)
+	}
+	return expr.String()
+}
@@ -0,0 +1,47 @@
+package checker
+
+import (
+	"testing"
+	"time"
+
+	promql "github.com/prometheus/prometheus/promql/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckRule_EvaluatesExpression(t *testing.T) {
+	fp := &fakeProber{values: map[string]float64{`up`: 3, `errors`: 2}}
+	fq := &fakeQuerier{counts: map[string]float64{
+		`up == 0`:                   2,
+		`vector(1 + 1)`:             1,
+		`sum by (job) (errors) > 5`: 0,
+	}}
+	prc := &PrometheusRulesChecker{probe: fp, query: fq, parser: promql.NewParser(promql.Options{}), evaluate: true}
+
+	tests := []struct {
+		name       string
+		expr       string
+		wantStatus RuleStatus
+		wantSeries int
+	}{
+		{"results", `up == 0`, RuleStatusResults, 2},
+		{"scalar", `1 + 1`, RuleStatusResults, 1},
+		{"empty", `sum by (job) (errors) > 5`, RuleStatusEmpty, 0},
+		{"broken", `missing > 5`, RuleStatusBroken, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := prc.checkRule(t.Context(), time.Now(), Rule{Name: "r", Expression: tt.expr})
+			require.NoError(t, err)
+			require.Equal(t, tt.wantStatus, got.Status)
+			require.Equal(t, tt.wantSeries, got.Series)
+		})
+	}
+}
+
+func TestCheckRule_DoesNotEvaluateByDefault(t *testing.T) {
+	prc := &PrometheusRulesChecker{probe: &fakeProber{}, query: &fakeQuerier{}, parser: promql.NewParser(promql.Options{})}
+
+	got, err := prc.checkRule(t.Context(), time.Now(), Rule{Name: "r", Expression: `up == 0`})
+	require.NoError(t, err)
+	require.Empty(t, got.Status)
+}
@@ -0,0 +1,182 @@
+package checker
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+	promql "github.com/prometheus/prometheus/promql/parser"
+)
+
+// MissingLabel represents a grouping or matching label which series of a selector lack.
+type MissingLabel struct {
+	// Label represents the label name of a by, on or group_left/group_right
+	// clause applying to the selector
+	Label string
+
+	// Series represents the number of the selector's series without the label
+	Series int
+
+	// Total represents the number of the selector's series checked for the
+	// label, over the lookback window if configured
+	Total int
+}
+
+// groupingLabels returns the labels the by, on and group_left/group_right
+// clauses of the expression require from the series of vs, given the path
+// of its ancestor nodes in the expression (outermost first). Walking up from
+// vs, the walk stops where the series of vs no longer make up the labels of
+// the result, e.g. at an aggregation or on the "one" side of a group_left.
+// Labels set by label_replace or label_join, or included by
+// group_left/group_right, on the way up aren't returned. The labels of
+// without and ignoring clauses aren't returned either, since dropping a
+// label the series lack is harmless.
+func groupingLabels(vs *promql.VectorSelector, path []promql.Node) []string {
+	var w labelWalk
+	var child promql.Node = vs
+	for i := len(path) - 1; i >= 0; i-- {
+		up := true
+		switch n := path[i].(type) {
+		case *promql.AggregateExpr:
+			up = w.aggregation(n, child)
+		case *promql.BinaryExpr:
+			up = w.binaryOp(n, child)
+		case *promql.Call:
+			up = w.call(n)
+		}
+		if !up {
+			break
+		}
+		child = path[i]
+	}
+	return w.required
+}
+
+// labelWalk collects the labels required from the series of a selector while
+// walking up its ancestor nodes, see groupingLabels.
+type labelWalk struct {
+	// required represents the labels required from the series so far
+	required []string
+
+	// added represents the labels set on the way up, which the series don't need to carry
+	added []string
+}
+
+// require adds the given labels to the required ones, unless set on the way up.
+func (w *labelWalk) require(names ...string) {
+	for _, name := range names {
+		if name == labels.MetricName || slices.Contains(w.added, name) || slices.Contains(w.required, name) {
+			continue
+		}
+		w.required = append(w.required, name)
+	}
+}
+
+// aggregation requires the by labels of n, an aggregation of child, and
+// reports whether the series of child make up its result.
+func (w *labelWalk) aggregation(n *promql.AggregateExpr, child promql.Node) bool {
+	if n.Param == child {
+		return false
+	}
+	if !n.Without {
+		w.require(n.Grouping...)
+	}
+	switch n.Op {
+	case promql.TOPK, promql.BOTTOMK, promql.LIMITK, promql.LIMIT_RATIO:
+		// keep their input series as is
+		return true
+	default:
+		return false
+	}
+}
+
+// binaryOp requires the on labels of n, a binary operation with child as an
+// operand, and reports whether the series of child make up its result.
+func (w *labelWalk) binaryOp(n *promql.BinaryExpr, child promql.Node) bool {
+	m := n.VectorMatching
+	if m == nil {
+		// one side is a scalar, the vector side keeps its labels
+		return true
+	}
+	if m.On {
+		w.require(m.MatchingLabels...)
+	}
+	lhs := n.LHS == child
+	switch m.Card {
+	case promql.CardManyToOne, promql.CardOneToMany:
+		if lhs == (m.Card == promql.CardManyToOne) {
+			// the "many" side, its labels make up the result
+			w.added = append(w.added, m.Include...)
+			return true
+		}
+		w.require(m.Include...)
+		return false
+	case promql.CardOneToOne:
+		return lhs && !m.On
+	case promql.CardManyToMany:
+		return lhs || n.Op == promql.LOR
+	}
+	return true
+}
+
+// call notes the labels n sets and reports whether its argument's series make up its result.
+func (w *labelWalk) call(n *promql.Call) bool {
+	switch n.Func.Name {
+	case "label_replace", "label_join":
+		if len(n.Args) > 1 {
+			if dst, ok := n.Args[1].(*promql.StringLiteral); ok {
+				w.added = append(w.added, dst.Val)
+			}
+		}
+	case "absent", "absent_over_time", "scalar", "vector", "info":
+		return false
+	}
+	return true
+}
+
+// probeGroupingLabels probes each of the given selectors with a result value
+// for series carrying each of the labels its grouping and matching clauses
+// apply to, see groupingLabels, and records the labels not all series carry
+// as MissingLabels in results. With a lookback window configured, the
+// selectors are probed over the window.
+func (prc *PrometheusRulesChecker) probeGroupingLabels(ctx context.Context, ts time.Time, selectors []ruleSelector, results *selectorResults) error {
+	for _, selector := range selectors {
+		if len(selector.groupingLabels) == 0 {
+			continue
+		}
+		matchers, err := prc.parser.ParseMetricSelector(selector.expr)
+		if err != nil {
+			return err
+		}
+		probe := func(ms []*labels.Matcher) (float64, error) {
+			s := selector.withExpr(selectorString(ms))
+			if prc.lookback > 0 {
+				s.window = max(prc.lookback, s.window)
+			}
+			return prc.probeSelector(ctx, s, ts)
+		}
+		total, err := probe(matchers)
+		if err != nil {
+			return err
+		}
+		for _, label := range selector.groupingLabels {
+			if slices.ContainsFunc(matchers, func(m *labels.Matcher) bool { return m.Name == label && !m.Matches("") }) {
+				// every series of the selector carries the label anyway
+				continue
+			}
+			withLabel, err := probe(append(slices.Clone(matchers), labels.MustNewMatcher(labels.MatchNotEqual, label, "")))
+			if err != nil {
+				return err
+			}
+			if withLabel >= total {
+				continue
+			}
+			r := results.get(selector.text)
+			if !slices.ContainsFunc(r.MissingLabels, func(m MissingLabel) bool { return m.Label == label }) {
+				r.MissingLabels = append(r.MissingLabels, MissingLabel{Label: label, Series: int(total - withLabel), Total: int(total)})
+			}
+		}
+	}
+	return nil
+}
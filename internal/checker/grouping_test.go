@@ -0,0 +1,89 @@
+package checker
+
+import (
+	"testing"
+	"time"
+
+	promql "github.com/prometheus/prometheus/promql/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupingLabels(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		want map[string][]string
+	}{
+		{"by", `sum by (team) (foo)`, map[string][]string{`foo`: {"team"}}},
+		{"without", `sum without (instance) (rate(foo[5m]))`, map[string][]string{`foo[5m]`: nil}},
+		{"without keeps the by labels further up", `sum by (team) (topk without (instance) (3, foo))`, map[string][]string{`foo`: {"team"}}},
+		{"metric name", `count by (__name__, job) ({job="x"})`, map[string][]string{`{job="x"}`: {"job"}}},
+		{"inner aggregation", `max by (team) (sum by (team, env) (foo))`, map[string][]string{`foo`: {"team", "env"}}},
+		{"topk keeps its input", `sum by (team) (topk by (env) (3, foo))`, map[string][]string{`foo`: {"env", "team"}}},
+		{"on", `foo / on (instance) bar`, map[string][]string{`foo`: {"instance"}, `bar`: {"instance"}}},
+		{"one-to-one on keeps the matching labels only", `sum by (team) (foo / on (instance) bar)`, map[string][]string{`foo`: {"instance"}, `bar`: {"instance"}}},
+		{"ignoring", `sum by (team) (foo / ignoring (code) bar)`, map[string][]string{`foo`: {"team"}, `bar`: nil}},
+		{
+			"group_left", `sum by (team, owner) (foo * on (instance) group_left (owner) info)`,
+			map[string][]string{`foo`: {"instance", "team"}, `info`: {"instance", "owner"}},
+		},
+		{
+			"group_right", `foo * on (instance) group_right (owner) bar`,
+			map[string][]string{`foo`: {"instance", "owner"}, `bar`: {"instance"}},
+		},
+		{"or", `sum by (team) (foo or bar)`, map[string][]string{`foo`: {"team"}, `bar`: {"team"}}},
+		{"unless", `sum by (team) (foo unless bar)`, map[string][]string{`foo`: {"team"}, `bar`: nil}},
+		{"label_replace", `sum by (team) (label_replace(foo, "team", "$1", "owner", "(.*)"))`, map[string][]string{`foo`: nil}},
+		{"absent", `sum by (team) (absent(foo))`, map[string][]string{`foo`: nil}},
+		{"scalar operand", `sum by (team) (foo > 3)`, map[string][]string{`foo`: {"team"}}},
+		{"no clauses", `rate(foo[5m])`, map[string][]string{`foo[5m]`: nil}},
+	}
+	p := promql.NewParser(promql.Options{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selectors, err := getRuleSelectors(p, tt.expr)
+			require.NoError(t, err)
+			got := map[string][]string{}
+			for _, s := range selectors {
+				got[s.text] = s.groupingLabels
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCheckRule_ReportsMissingGroupingLabels(t *testing.T) {
+	fp := &fakeProber{values: map[string]float64{
+		`foo{job="x"}`:          5,
+		`foo{job="x",team!=""}`: 2,
+		`foo{env!="",job="x"}`:  5,
+		`bar{team="a"}`:         1,
+		`bar{env!="",team="a"}`: 0,
+		`baz`:                   1,
+	}}
+	prc := &PrometheusRulesChecker{probe: fp, parser: promql.NewParser(promql.Options{}), groupingLabels: true}
+
+	got, err := prc.checkRule(t.Context(), time.Now(), Rule{Name: "r", Expression: `sum by (team, env) (foo{job="x"} or bar{team="a"}) or baz`})
+	require.NoError(t, err)
+	require.Equal(t, []string{`foo{job="x"}`, `bar{team="a"}`, `baz`}, got.Results, "missing labels must not fail the selector")
+	require.Equal(t, []SelectorResult{
+		{Selector: `foo{job="x"}`, Series: 5, MissingLabels: []MissingLabel{{Label: "team", Series: 3, Total: 5}}},
+		{Selector: `bar{team="a"}`, Series: 1, MissingLabels: []MissingLabel{{Label: "env", Series: 1, Total: 1}}},
+		{Selector: `baz`, Series: 1},
+	}, got.Selectors)
+	require.NotContains(t, fp.calls, `bar{team!="",team="a"}`, "labels with a matcher not matching the empty value need no probe")
+}
+
+func TestCheckRule_CountsMissingGroupingLabelsOverLookbackWindow(t *testing.T) {
+	fp := &fakeProber{
+		values:      map[string]float64{`foo`: 3},
+		rangeValues: map[string]float64{`foo`: 5, `foo{team!=""}`: 1},
+	}
+	prc := &PrometheusRulesChecker{probe: fp, parser: promql.NewParser(promql.Options{}), groupingLabels: true, lookback: time.Hour}
+
+	got, err := prc.checkRule(t.Context(), time.Now(), Rule{Name: "r", Expression: `sum by (team) (foo)`})
+	require.NoError(t, err)
+	require.Equal(t, []SelectorResult{
+		{Selector: `foo`, Presence: PresenceNow, Series: 3, MissingLabels: []MissingLabel{{Label: "team", Series: 4, Total: 5}}},
+	}, got.Selectors, "missing labels are counted against the series of the lookback window")
+}
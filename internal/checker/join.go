@@ -0,0 +1,158 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	promql "github.com/prometheus/prometheus/promql/parser"
+)
+
+// EmptyJoin represents a binary operation with vector matching whose operands
+// both yield series, but whose match keys never overlap, so the operation
+// never yields a result.
+type EmptyJoin struct {
+	// Expression represents the binary operation, e.g. a * on (namespace, pod) group_left () b
+	Expression string
+
+	// Matching represents the matching clause of the operation, e.g.
+	// on (namespace, pod), empty if all labels but the metric name are matched
+	Matching string
+
+	// LHS represents the number of distinct match keys of the left-hand side
+	LHS int
+
+	// RHS represents the number of distinct match keys of the right-hand side
+	RHS int
+
+	// LHSExample represents a match key of the left-hand side, e.g. {namespace="a", pod="b"}
+	LHSExample string
+
+	// RHSExample represents a match key of the right-hand side
+	RHSExample string
+}
+
+// probeJoins evaluates both operands of every binary operation with vector
+// matching of the given expression at the evaluation timestamp ts, grouped by
+// their match keys. Operations whose operands both yield match keys, but
+// never the same, are returned as EmptyJoin. Set operations are left out,
+// since "or" doesn't join and "unless" not matching anything is no error,
+// and so are operations with an ignored selector.
+func (prc *PrometheusRulesChecker) probeJoins(ctx context.Context, ts time.Time, promqlExpression string) ([]EmptyJoin, error) {
+	expr, err := prc.parser.ParseExpr(promqlExpression)
+	if err != nil {
+		return nil, fmt.Errorf("promql parse error: %w", err)
+	}
+	var joins []*promql.BinaryExpr
+	promql.Inspect(expr, func(node promql.Node, _ []promql.Node) error {
+		if n, ok := node.(*promql.BinaryExpr); ok && n.VectorMatching != nil && n.Op != promql.LOR && n.Op != promql.LUNLESS {
+			joins = append(joins, n)
+		}
+		return nil
+	})
+
+	var empty []EmptyJoin
+	for _, join := range joins {
+		skip, err := prc.hasIgnoredSelector(join)
+		if err != nil {
+			return nil, err
+		}
+		if skip {
+			continue
+		}
+		lhs, err := prc.matchKeys(ctx, ts, join.LHS, join.VectorMatching)
+		if err != nil {
+			return nil, err
+		}
+		rhs, err := prc.matchKeys(ctx, ts, join.RHS, join.VectorMatching)
+		if err != nil {
+			return nil, err
+		}
+		if len(lhs) == 0 || len(rhs) == 0 || overlaps(lhs, rhs) {
+			continue
+		}
+		empty = append(empty, EmptyJoin{
+			Expression: join.String(),
+			Matching:   matchingString(join.VectorMatching),
+			LHS:        len(lhs),
+			RHS:        len(rhs),
+			LHSExample: firstKey(lhs),
+			RHSExample: firstKey(rhs),
+		})
+	}
+	return empty, nil
+}
+
+// hasIgnoredSelector reports whether any selector of the given expression is ignored.
+func (prc *PrometheusRulesChecker) hasIgnoredSelector(expr promql.Node) (bool, error) {
+	var selectors []ruleSelector
+	promql.Inspect(expr, func(node promql.Node, _ []promql.Node) error {
+		if vs, ok := node.(*promql.VectorSelector); ok {
+			bare := promql.VectorSelector{Name: vs.Name, LabelMatchers: vs.LabelMatchers}
+			selectors = append(selectors, ruleSelector{expr: bare.String()})
+		}
+		return nil
+	})
+	for _, selector := range selectors {
+		skip, err := prc.skipSelector(selector)
+		if err != nil || skip {
+			return skip, err
+		}
+	}
+	return false, nil
+}
+
+// matchKeys returns the distinct match keys the given operand of a binary
+// operation yields at ts, honoring the configured probe concurrency bound.
+func (prc *PrometheusRulesChecker) matchKeys(ctx context.Context, ts time.Time, operand promql.Expr, matching *promql.VectorMatching) (map[string]struct{}, error) {
+	// count without () drops the metric name, which is never matched
+	query := &promql.AggregateExpr{Op: promql.COUNT, Expr: operand, Grouping: matching.MatchingLabels, Without: !matching.On}
+	if err := prc.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer prc.release()
+	sets, err := prc.query.LabelSets(ctx, query.String(), ts)
+	if err != nil {
+		return nil, err
+	}
+	keys := make(map[string]struct{}, len(sets))
+	for _, set := range sets {
+		keys[set.String()] = struct{}{}
+	}
+	return keys, nil
+}
+
+// overlaps reports whether a and b share a key.
+func overlaps(a, b map[string]struct{}) bool {
+	for key := range a {
+		if _, ok := b[key]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// firstKey returns the smallest key of keys, so examples are stable across runs.
+func firstKey(keys map[string]struct{}) string {
+	var first string
+	for key := range keys {
+		if first == "" || key < first {
+			first = key
+		}
+	}
+	return first
+}
+
+// matchingString renders the matching clause of a binary operation, empty if
+// it matches on all labels but the metric name.
+func matchingString(m *promql.VectorMatching) string {
+	switch {
+	case m.On:
+		return fmt.Sprintf("on (%s)", strings.Join(m.MatchingLabels, ", "))
+	case len(m.MatchingLabels) > 0:
+		return fmt.Sprintf("ignoring (%s)", strings.Join(m.MatchingLabels, ", "))
+	default:
+		return ""
+	}
+}
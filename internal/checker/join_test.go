@@ -0,0 +1,83 @@
+package checker
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	promql "github.com/prometheus/prometheus/promql/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProbeJoins(t *testing.T) {
+	fq := &fakeQuerier{labelSets: map[string][]model.Metric{
+		`count by (pod) (foo)`: {{"pod": "a"}, {"pod": "b"}},
+		`count by (pod) (bar)`: {{"pod": "A"}},
+		`count by (pod) (baz)`: {{"pod": "b"}},
+		`count without () (x)`: {{"job": "1"}},
+		`count without () (y)`: {{"job": "1"}},
+	}}
+	prc := &PrometheusRulesChecker{
+		query:                  fq,
+		parser:                 promql.NewParser(promql.Options{}),
+		ignoredSelectorsRegexp: []*regexp.Regexp{regexp.MustCompile("ignored")},
+	}
+
+	tests := []struct {
+		name string
+		expr string
+		want []EmptyJoin
+	}{
+		{
+			"disjoint", `foo * on (pod) group_left () bar`,
+			[]EmptyJoin{{
+				Expression: `foo * on (pod) group_left () bar`, Matching: "on (pod)",
+				LHS: 2, RHS: 1, LHSExample: `{pod="a"}`, RHSExample: `{pod="A"}`,
+			}},
+		},
+		{"overlapping", `foo * on (pod) baz`, nil},
+		{"all labels", `x / y`, nil},
+		{"empty operand", `foo * on (pod) missing`, nil},
+		{"or and unless", `foo or on (pod) bar unless on (pod) foo`, nil},
+		{
+			"and", `foo and on (pod) bar`,
+			[]EmptyJoin{{
+				Expression: `foo and on (pod) bar`, Matching: "on (pod)",
+				LHS: 2, RHS: 1, LHSExample: `{pod="a"}`, RHSExample: `{pod="A"}`,
+			}},
+		},
+		{"ignored", `foo * on (pod) ignored`, nil},
+		{"scalar", `foo * 2`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := prc.probeJoins(t.Context(), time.Now(), tt.expr)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMatchingString(t *testing.T) {
+	require.Equal(t, "on (a, b)", matchingString(&promql.VectorMatching{On: true, MatchingLabels: []string{"a", "b"}}))
+	require.Equal(t, "ignoring (c)", matchingString(&promql.VectorMatching{MatchingLabels: []string{"c"}}))
+	require.Empty(t, matchingString(&promql.VectorMatching{}))
+}
+
+func TestCheckRule_ReportsEmptyJoins(t *testing.T) {
+	fp := &fakeProber{values: map[string]float64{`foo`: 2, `bar`: 1}}
+	fq := &fakeQuerier{labelSets: map[string][]model.Metric{
+		`count by (pod) (foo)`: {{"pod": "a"}},
+		`count by (pod) (bar)`: {{"pod": "b"}},
+	}}
+	prc := &PrometheusRulesChecker{probe: fp, query: fq, parser: promql.NewParser(promql.Options{}), joins: true}
+
+	got, err := prc.checkRule(t.Context(), time.Now(), Rule{Name: "r", Expression: `foo / on (pod) bar`})
+	require.NoError(t, err)
+	require.Equal(t, []string{`foo`, `bar`}, got.Results, "empty joins must not fail the selectors")
+	require.Equal(t, []EmptyJoin{{
+		Expression: `foo / on (pod) bar`, Matching: "on (pod)",
+		LHS: 1, RHS: 1, LHSExample: `{pod="a"}`, RHSExample: `{pod="b"}`,
+	}}, got.EmptyJoins)
+}
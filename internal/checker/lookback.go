@@ -0,0 +1,71 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Presence represents when a selector returned a result value.
+type Presence string
+
+const (
+	// PresenceNow means the selector returned a result value at the evaluation timestamp.
+	PresenceNow Presence = "present"
+
+	// PresenceWindow means the selector returned a result value within the lookback window only.
+	PresenceWindow Presence = "window"
+
+	// PresenceAbsent means the selector returned no result value within the lookback window.
+	PresenceAbsent Presence = "absent"
+)
+
+// probeLookback probes the failed selectors again over the configured lookback
+// window ending at their evaluation timestamp, and classifies every selector's
+// Presence in results.
+// probeLookback returns the selectors with a result value (at ts or within the
+// window) and the selectors without any result value in the window. The query
+// warnings and series counts of the lookback probes are recorded in results, too.
+func (prc *PrometheusRulesChecker) probeLookback(ctx context.Context, ts time.Time, success, failed []ruleSelector, results *selectorResults) ([]ruleSelector, []ruleSelector, error) {
+	for _, selector := range success {
+		results.get(selector.text).Presence = PresenceNow
+	}
+
+	absent := make([]ruleSelector, 0, len(failed))
+	for _, selector := range failed {
+		window := max(prc.lookback, selector.window)
+		probeCtx, warnings := withWarnings(ctx)
+		val, err := prc.probeSelectorRange(probeCtx, selector.expr, selector.evalTime(ts), window)
+		if err != nil {
+			return success, failed, err
+		}
+		r := results.get(selector.text)
+		r.addWarnings(warnings.list())
+		prc.recordSeries(r, selector, val)
+		if val < 1 {
+			r.Presence = PresenceAbsent
+			absent = append(absent, selector)
+			continue
+		}
+		r.Presence = PresenceWindow
+		success = append(success, selector)
+	}
+	return success, absent, nil
+}
+
+// probeSelectorRange probes a single selector for a result value anywhere in
+// the window ending at the evaluation timestamp ts, honoring the configured
+// probe concurrency bound and the run's probe cache.
+func (prc *PrometheusRulesChecker) probeSelectorRange(ctx context.Context, selector string, ts time.Time, window time.Duration) (float64, error) {
+	rp, ok := prc.probe.(RangeProber)
+	if !ok {
+		return 0, fmt.Errorf("prober %T does not support lookback windows", prc.probe)
+	}
+	return prc.cachedProbe(ctx, selector, ts, window, func(ctx context.Context) (float64, error) {
+		if err := prc.acquire(ctx); err != nil {
+			return 0, err
+		}
+		defer prc.release()
+		return rp.ProbeSelectorRange(ctx, selector, ts, window)
+	})
+}
@@ -0,0 +1,53 @@
+package checker
+
+import (
+	"testing"
+	"time"
+
+	promql "github.com/prometheus/prometheus/promql/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckRule_ClassifiesPresenceWithinLookback(t *testing.T) {
+	fp := &fakeProber{
+		values:      map[string]float64{`up{job="now"}`: 1},
+		rangeValues: map[string]float64{`batch_job_last_success{job="nightly"}`: 1},
+	}
+	prc := &PrometheusRulesChecker{probe: fp, parser: promql.NewParser(promql.Options{}), lookback: 24 * time.Hour}
+
+	got, err := prc.checkRule(t.Context(), time.Now(), Rule{
+		Name:       "r",
+		Expression: `up{job="now"} or batch_job_last_success{job="nightly"} or gone{job="x"}`,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{`up{job="now"}`, `batch_job_last_success{job="nightly"}`}, got.Results)
+	require.Equal(t, []string{`gone{job="x"}`}, got.NoResults)
+	require.Equal(t, []SelectorResult{
+		{Selector: `up{job="now"}`, Presence: PresenceNow, Series: 1},
+		{Selector: `batch_job_last_success{job="nightly"}`, Presence: PresenceWindow, Series: 1},
+		{Selector: `gone{job="x"}`, Presence: PresenceAbsent},
+	}, got.Selectors)
+	require.Equal(t, 24*time.Hour, fp.windows[`gone{job="x"}`])
+	require.NotContains(t, fp.windows, `up{job="now"}`, "selectors present now must not be probed again")
+}
+
+func TestCheckRule_ChecksSeriesLimitOverLookbackWindow(t *testing.T) {
+	fp := &fakeProber{rangeValues: map[string]float64{`batch_job_last_success`: 250}}
+	prc := &PrometheusRulesChecker{probe: fp, parser: promql.NewParser(promql.Options{}), lookback: 24 * time.Hour, maxSeries: 100}
+
+	got, err := prc.checkRule(t.Context(), time.Now(), Rule{Name: "r", Expression: `batch_job_last_success`})
+	require.NoError(t, err)
+	require.Equal(t, []SelectorResult{
+		{Selector: `batch_job_last_success`, Presence: PresenceWindow, Series: 250, SeriesLimit: 100},
+	}, got.Selectors)
+}
+
+func TestCheckRule_NoLookbackLeavesPresenceUnset(t *testing.T) {
+	fp := &fakeProber{values: map[string]float64{`up`: 1}}
+	prc := &PrometheusRulesChecker{probe: fp, parser: promql.NewParser(promql.Options{})}
+
+	got, err := prc.checkRule(t.Context(), time.Now(), Rule{Name: "r", Expression: `up`})
+	require.NoError(t, err)
+	require.Equal(t, []SelectorResult{{Selector: `up`, Series: 1}}, got.Selectors)
+	require.Empty(t, fp.windows)
+}
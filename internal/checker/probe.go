@@ -16,27 +16,77 @@ type Prober interface {
 	ProbeSelector(ctx context.Context, selector string, ts time.Time) (float64, error)
 }
 
+// RangeProber represents a Prober which can also probe a selector over a window.
+type RangeProber interface {
+	// ProbeSelectorRange probes the given PromQL selector against a remote
+	// instance for a result value anywhere in the window ending at the given
+	// timestamp ts.
+	ProbeSelectorRange(ctx context.Context, selector string, ts time.Time, window time.Duration) (float64, error)
+}
+
+// Querier represents ad-hoc queries against a remote instance beyond selector probes.
+type Querier interface {
+	// LabelValues returns the values of the given label across all series
+	// matching any of the given selectors (all series if none are given)
+	// with samples between start and end.
+	LabelValues(ctx context.Context, label string, selectors []string, start, end time.Time) ([]string, error)
+
+	// LastSeen returns the timestamp of the newest sample of the given
+	// selector in the window ending at the given timestamp ts, or the zero
+	// time if there is none.
+	LastSeen(ctx context.Context, selector string, ts time.Time, window time.Duration) (time.Time, error)
+
+	// LabelSets returns the label sets of the series the given PromQL
+	// expression yields at the given timestamp ts.
+	LabelSets(ctx context.Context, expr string, ts time.Time) ([]model.Metric, error)
+
+	// Count returns the number of series the given PromQL expression yields
+	// at the given timestamp ts.
+	Count(ctx context.Context, expr string, ts time.Time) (float64, error)
+
+	// QueryRange returns the series the given PromQL expression yields when
+	// evaluated every step from start to end.
+	QueryRange(ctx context.Context, expr string, start, end time.Time, step time.Duration) (model.Matrix, error)
+}
+
 type prometheusProbe struct {
 	api           prometheusv1.API
 	prometheusURL string
+
+	// retry retries failed queries, a nil retry queries once
+	retry *retrier
+
+	// limit rate limits queries, a nil limit does not limit
+	limit *rateLimiter
+
+	// opts represents the options passed with every query, e.g. a timeout
+	opts []prometheusv1.Option
 }
 
-func newPrometheusProbe(prometheusURL string, client prometheusv1.API) Prober {
+func newPrometheusProbe(prometheusURL string, client prometheusv1.API, retry *retrier, limit *rateLimiter, opts ...prometheusv1.Option) *prometheusProbe {
 	return &prometheusProbe{
 		api:           client,
 		prometheusURL: prometheusURL,
+		retry:         retry,
+		limit:         limit,
+		opts:          opts,
 	}
 }
 
 func (p *prometheusProbe) probe(ctx context.Context, selector string, ts time.Time) (float64, error) {
-	query := fmt.Sprintf("count(%s)", selector)
-	value, _, err := p.api.Query(ctx, query, ts)
+	return p.count(ctx, selector, ts)
+}
+
+// count returns the number of series the given PromQL expression yields at ts.
+func (p *prometheusProbe) count(ctx context.Context, expr string, ts time.Time) (float64, error) {
+	query := fmt.Sprintf("count(%s)", expr)
+	value, err := p.query(ctx, query, ts)
 	if err != nil {
 		return 0, fmt.Errorf("failed to query metrics: %w", err)
 	}
 	vec, ok := value.(model.Vector)
 	if !ok {
-		return 0, fmt.Errorf("unexpected query result type %T for selector %q (wanted vector)", value, selector)
+		return 0, fmt.Errorf("unexpected query result type %T for %q (wanted vector)", value, expr)
 	}
 	var metricValue float64
 	for _, v := range vec {
@@ -49,7 +99,109 @@ func (p *prometheusProbe) probe(ctx context.Context, selector string, ts time.Ti
 	return metricValue, nil
 }
 
+// query evaluates the given PromQL query at ts, rate limited and retrying
+// retryable failures. The warnings of the query are recorded in the
+// warningCollector of ctx, if any.
+func (p *prometheusProbe) query(ctx context.Context, query string, ts time.Time) (model.Value, error) {
+	var (
+		value    model.Value
+		warnings prometheusv1.Warnings
+	)
+	err := callRemote(ctx, p.retry, p.limit, func() (err error) {
+		value, warnings, err = p.api.Query(ctx, query, ts, p.opts...)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	addWarnings(ctx, warnings)
+	return value, nil
+}
+
 // ProbeSelector implements Prober.
 func (p *prometheusProbe) ProbeSelector(ctx context.Context, selector string, ts time.Time) (float64, error) {
 	return p.probe(ctx, selector, ts)
 }
+
+// ProbeSelectorRange implements RangeProber.
+func (p *prometheusProbe) ProbeSelectorRange(ctx context.Context, selector string, ts time.Time, window time.Duration) (float64, error) {
+	return p.count(ctx, fmt.Sprintf("last_over_time(%s[%s])", selector, model.Duration(window)), ts)
+}
+
+// LabelValues implements Querier.
+func (p *prometheusProbe) LabelValues(ctx context.Context, label string, selectors []string, start, end time.Time) ([]string, error) {
+	var values model.LabelValues
+	err := callRemote(ctx, p.retry, p.limit, func() (err error) {
+		values, _, err = p.api.LabelValues(ctx, label, selectors, start, end, p.opts...)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query label values: %w", err)
+	}
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		out = append(out, string(v))
+	}
+	return out, nil
+}
+
+// LastSeen implements Querier.
+func (p *prometheusProbe) LastSeen(ctx context.Context, selector string, ts time.Time, window time.Duration) (time.Time, error) {
+	query := fmt.Sprintf("max(max_over_time(timestamp(%s)[%s:]))", selector, model.Duration(window))
+	value, err := p.query(ctx, query, ts)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to query last seen timestamp: %w", err)
+	}
+	vec, ok := value.(model.Vector)
+	if !ok {
+		return time.Time{}, fmt.Errorf("unexpected query result type %T for %q (wanted vector)", value, query)
+	}
+	if len(vec) == 0 {
+		return time.Time{}, nil
+	}
+	seconds := float64(vec[0].Value)
+	return time.Unix(0, int64(seconds*float64(time.Second))), nil
+}
+
+// LabelSets implements Querier.
+func (p *prometheusProbe) LabelSets(ctx context.Context, expr string, ts time.Time) ([]model.Metric, error) {
+	value, err := p.query(ctx, expr, ts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query label sets: %w", err)
+	}
+	vec, ok := value.(model.Vector)
+	if !ok {
+		return nil, fmt.Errorf("unexpected query result type %T for %q (wanted vector)", value, expr)
+	}
+	sets := make([]model.Metric, 0, len(vec))
+	for _, sample := range vec {
+		sets = append(sets, sample.Metric)
+	}
+	return sets, nil
+}
+
+// Count implements Querier.
+func (p *prometheusProbe) Count(ctx context.Context, expr string, ts time.Time) (float64, error) {
+	return p.count(ctx, expr, ts)
+}
+
+// QueryRange implements Querier.
+func (p *prometheusProbe) QueryRange(ctx context.Context, expr string, start, end time.Time, step time.Duration) (model.Matrix, error) {
+	var (
+		value    model.Value
+		warnings prometheusv1.Warnings
+	)
+	err := callRemote(ctx, p.retry, p.limit, func() (err error) {
+		value, warnings, err = p.api.QueryRange(ctx, expr, prometheusv1.Range{Start: start, End: end, Step: step}, p.opts...)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query range: %w", err)
+	}
+	addWarnings(ctx, warnings)
+	matrix, ok := value.(model.Matrix)
+	if !ok {
+		return nil, fmt.Errorf("unexpected query result type %T for %q (wanted matrix)", value, expr)
+	}
+	return matrix, nil
+}
@@ -18,10 +18,13 @@ type fakeAPI struct {
 
 	// gotTS records the evaluation timestamp passed to the last Query call
 	gotTS time.Time
+	// gotQuery records the query passed to the last Query call
+	gotQuery string
 }
 
-func (f *fakeAPI) Query(_ context.Context, _ string, ts time.Time, _ ...prometheusv1.Option) (model.Value, prometheusv1.Warnings, error) {
+func (f *fakeAPI) Query(_ context.Context, query string, ts time.Time, _ ...prometheusv1.Option) (model.Value, prometheusv1.Warnings, error) {
 	f.gotTS = ts
+	f.gotQuery = query
 	return f.value, f.warnings, f.err
 }
 
@@ -49,3 +52,57 @@ func TestProbe_UsesGivenTimestamp(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, api.gotTS.Equal(ts), "probe must query at the given ts, got %v want %v", api.gotTS, ts)
 }
+
+func TestProbe_ProbeSelectorRangeUsesLastOverTime(t *testing.T) {
+	api := &fakeAPI{value: model.Vector{&model.Sample{Value: 2}}}
+	p := &prometheusProbe{api: api}
+	v, err := p.ProbeSelectorRange(context.Background(), `up{job="x"}`, time.Now(), 24*time.Hour)
+	require.NoError(t, err)
+	require.Equal(t, float64(2), v)
+	require.Equal(t, `count(last_over_time(up{job="x"}[1d]))`, api.gotQuery)
+}
+
+type fakeLabelValuesAPI struct {
+	prometheusv1.API
+	values model.LabelValues
+
+	gotLabel   string
+	gotMatches []string
+	gotStart   time.Time
+	gotEnd     time.Time
+}
+
+func (f *fakeLabelValuesAPI) LabelValues(_ context.Context, label string, matches []string, start, end time.Time, _ ...prometheusv1.Option) (model.LabelValues, prometheusv1.Warnings, error) {
+	f.gotLabel = label
+	f.gotMatches = matches
+	f.gotStart, f.gotEnd = start, end
+	return f.values, nil, nil
+}
+
+func TestProbe_LabelValues(t *testing.T) {
+	api := &fakeLabelValuesAPI{values: model.LabelValues{"a", "b"}}
+	p := &prometheusProbe{api: api}
+	end := time.Now()
+	got, err := p.LabelValues(context.Background(), "job", []string{`up`}, end.Add(-time.Hour), end)
+	require.NoError(t, err)
+	require.Equal(t, []string{"a", "b"}, got)
+	require.Equal(t, "job", api.gotLabel)
+	require.Equal(t, []string{`up`}, api.gotMatches)
+	require.Equal(t, end.Add(-time.Hour), api.gotStart)
+	require.Equal(t, end, api.gotEnd)
+}
+
+func TestProbe_LastSeen(t *testing.T) {
+	seen := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	api := &fakeAPI{value: model.Vector{&model.Sample{Value: model.SampleValue(seen.Unix())}}}
+	p := &prometheusProbe{api: api}
+	got, err := p.LastSeen(context.Background(), `up`, time.Now(), time.Hour)
+	require.NoError(t, err)
+	require.True(t, got.Equal(seen), "got %v want %v", got, seen)
+	require.Equal(t, `max(max_over_time(timestamp(up)[1h:]))`, api.gotQuery)
+
+	api.value = model.Vector{}
+	got, err = p.LastSeen(context.Background(), `up`, time.Now(), time.Hour)
+	require.NoError(t, err)
+	require.True(t, got.IsZero())
+}
@@ -0,0 +1,158 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"golang.org/x/time/rate"
+)
+
+const (
+	// adaptiveSlowdownFactor marks a response as slow if it took this many
+	// times longer than the moving average of previous responses.
+	adaptiveSlowdownFactor = 2.0
+
+	// adaptiveMinSamples represents the number of responses the moving average
+	// is based on before responses are considered slow.
+	adaptiveMinSamples = 5
+
+	// adaptiveLatencyWeight represents the weight of a single response in the moving average.
+	adaptiveLatencyWeight = 0.2
+
+	// adaptiveMinRate represents the share of the configured rate the adaptive
+	// mode never backs off below.
+	adaptiveMinRate = 1.0 / 16
+
+	// adaptiveRecovery represents the share of the configured rate every fast
+	// response adds back after a backoff.
+	adaptiveRecovery = 1.0 / 20
+
+	// adaptiveBackoffInterval bounds how often the rate is halved, so a single
+	// slowdown reported by many in-flight queries halves it only once.
+	adaptiveBackoffInterval = time.Second
+)
+
+// rateLimiter limits remote calls to a rate of queries per second with a
+// token bucket. In adaptive mode, it halves the rate whenever Prometheus
+// responds slowly or reports being overloaded, and slowly restores the
+// configured rate as responses are fast again. A nil rateLimiter does not limit.
+type rateLimiter struct {
+	limiter  *rate.Limiter
+	qps      float64
+	adaptive bool
+
+	// now returns the current time
+	now func() time.Time
+
+	mu          sync.Mutex
+	latency     time.Duration
+	samples     int
+	lastBackoff time.Time
+	backoffs    int
+}
+
+func newRateLimiter(qps float64, burst int, adaptive bool) *rateLimiter {
+	if qps <= 0 {
+		return nil
+	}
+	return &rateLimiter{
+		limiter:  rate.NewLimiter(rate.Limit(qps), max(burst, 1)),
+		qps:      qps,
+		adaptive: adaptive,
+		now:      time.Now,
+	}
+}
+
+// do waits for the rate limit, then calls call.
+func (l *rateLimiter) do(ctx context.Context, call func() error) error {
+	if l == nil {
+		return call()
+	}
+	if err := l.limiter.Wait(ctx); err != nil {
+		return err
+	}
+	if !l.adaptive {
+		return call()
+	}
+	start := l.now()
+	err := call()
+	l.observe(l.now().Sub(start), err)
+	return err
+}
+
+// observe adapts the rate to a call which took d and failed with err, if any.
+func (l *rateLimiter) observe(d time.Duration, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	slow := false
+	if err == nil {
+		slow = l.samples >= adaptiveMinSamples && float64(d) > adaptiveSlowdownFactor*float64(l.latency)
+		if l.samples == 0 {
+			l.latency = d
+		} else {
+			l.latency += time.Duration(adaptiveLatencyWeight * float64(d-l.latency))
+		}
+		l.samples++
+	}
+
+	current := float64(l.limiter.Limit())
+	switch {
+	case slow || isOverloaded(err):
+		now := l.now()
+		if now.Sub(l.lastBackoff) < adaptiveBackoffInterval {
+			return
+		}
+		l.lastBackoff = now
+		l.backoffs++
+		l.limiter.SetLimit(rate.Limit(max(current/2, l.qps*adaptiveMinRate)))
+	case err == nil && current < l.qps:
+		l.limiter.SetLimit(rate.Limit(min(current+l.qps*adaptiveRecovery, l.qps)))
+	}
+}
+
+// resetBackoffs resets the number of backoffs, the current rate is kept.
+func (l *rateLimiter) resetBackoffs() {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.backoffs = 0
+}
+
+// backoffCount returns the number of backoffs since the last resetBackoffs.
+func (l *rateLimiter) backoffCount() int {
+	if l == nil {
+		return 0
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.backoffs
+}
+
+// isOverloaded reports whether err tells Prometheus is overloaded: a 429 or 503 response.
+func isOverloaded(err error) bool {
+	var apiErr *prometheusv1.Error
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.Type {
+	case prometheusv1.ErrClient:
+		return strings.HasSuffix(apiErr.Msg, "429")
+	case prometheusv1.ErrServer:
+		return strings.HasSuffix(apiErr.Msg, "503")
+	}
+	return false
+}
+
+// callRemote makes a remote call: rate limited by limit and retried by retry.
+func callRemote(ctx context.Context, retry *retrier, limit *rateLimiter, call func() error) error {
+	return retry.do(ctx, func() error {
+		return limit.do(ctx, call)
+	})
+}
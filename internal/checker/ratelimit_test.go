@@ -0,0 +1,117 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestRateLimiter returns an adaptive rateLimiter with a controllable clock.
+func newTestRateLimiter(qps float64) (*rateLimiter, *time.Time) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	l := newRateLimiter(qps, 1, true)
+	l.now = func() time.Time { return now }
+	return l, &now
+}
+
+// nextWait returns how long a call waits for the rate limit once the burst is
+// used up, without taking any tokens.
+func nextWait(l *rateLimiter) time.Duration {
+	at := time.Now()
+	first, second := l.limiter.ReserveN(at, 1), l.limiter.ReserveN(at, 1)
+	defer first.CancelAt(at)
+	defer second.CancelAt(at)
+	return second.DelayFrom(at) - first.DelayFrom(at)
+}
+
+// requireQPS asserts that calls are let through at qps queries per second.
+func requireQPS(t *testing.T, l *rateLimiter, qps float64, msgAndArgs ...any) {
+	t.Helper()
+	require.InDelta(t, float64(time.Second)/qps, float64(nextWait(l)), float64(time.Microsecond), msgAndArgs...)
+}
+
+func TestRateLimiter_LimitsRate(t *testing.T) {
+	l := newRateLimiter(100, 1, false)
+	start := time.Now()
+	for range 6 {
+		require.NoError(t, l.do(context.Background(), func() error { return nil }))
+	}
+	// the burst covers the first call, the other 5 wait 10ms each
+	require.GreaterOrEqual(t, time.Since(start), 45*time.Millisecond)
+}
+
+func TestRateLimiter_NilDoesNotLimit(t *testing.T) {
+	require.Nil(t, newRateLimiter(0, 10, true))
+
+	var l *rateLimiter
+	calls := 0
+	require.NoError(t, l.do(context.Background(), func() error { calls++; return nil }))
+	require.Equal(t, 1, calls)
+	require.Zero(t, l.backoffCount())
+}
+
+func TestRateLimiter_WaitHonorsContext(t *testing.T) {
+	l := newRateLimiter(0.001, 1, false)
+	require.NoError(t, l.do(context.Background(), func() error { return nil }))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	err := l.do(ctx, func() error { t.Fatal("must not be called"); return nil })
+	require.Error(t, err)
+}
+
+func TestRateLimiter_AdaptiveBacksOffWhenOverloaded(t *testing.T) {
+	l, now := newTestRateLimiter(100)
+
+	l.observe(10*time.Millisecond, &prometheusv1.Error{Type: prometheusv1.ErrClient, Msg: "client error: 429"})
+	requireQPS(t, l, 50.0)
+
+	// further overload reports within the backoff interval don't halve it again
+	l.observe(10*time.Millisecond, &prometheusv1.Error{Type: prometheusv1.ErrServer, Msg: "server error: 503"})
+	requireQPS(t, l, 50.0)
+
+	*now = now.Add(adaptiveBackoffInterval)
+	l.observe(10*time.Millisecond, &prometheusv1.Error{Type: prometheusv1.ErrServer, Msg: "server error: 503"})
+	requireQPS(t, l, 25.0)
+	require.Equal(t, 2, l.backoffCount())
+
+	// other errors don't affect the rate
+	*now = now.Add(adaptiveBackoffInterval)
+	l.observe(10*time.Millisecond, &prometheusv1.Error{Type: prometheusv1.ErrBadData})
+	l.observe(10*time.Millisecond, errors.New("boom"))
+	requireQPS(t, l, 25.0)
+}
+
+func TestRateLimiter_AdaptiveBacksOffWhenSlowAndRecovers(t *testing.T) {
+	l, now := newTestRateLimiter(100)
+	for range adaptiveMinSamples {
+		l.observe(10*time.Millisecond, nil)
+	}
+	requireQPS(t, l, 100.0)
+
+	l.observe(100*time.Millisecond, nil)
+	requireQPS(t, l, 50.0, "a slow response must halve the rate")
+
+	*now = now.Add(adaptiveBackoffInterval)
+	for range 100 {
+		l.observe(time.Millisecond, nil)
+	}
+	requireQPS(t, l, 100.0, "fast responses must restore the configured rate, but not exceed it")
+}
+
+func TestRateLimiter_AdaptiveNeverBacksOffBelowMinimum(t *testing.T) {
+	l, now := newTestRateLimiter(16)
+	for range 10 {
+		*now = now.Add(adaptiveBackoffInterval)
+		l.observe(time.Millisecond, &prometheusv1.Error{Type: prometheusv1.ErrClient, Msg: "client error: 429"})
+	}
+	requireQPS(t, l, 1.0)
+
+	l.resetBackoffs()
+	require.Zero(t, l.backoffCount())
+	requireQPS(t, l, 1.0, "resetting the statistics must keep the current rate")
+}
@@ -0,0 +1,187 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+)
+
+// errorClass represents the class of an error returned by a remote call.
+type errorClass string
+
+const (
+	// errorClassNetwork represents transient network errors, e.g. connection resets or timeouts.
+	errorClassNetwork errorClass = "network"
+
+	// errorClassServer represents transient Prometheus errors, e.g. query
+	// timeouts, execution errors, 5xx responses or rate limiting.
+	errorClassServer errorClass = "server"
+
+	// errorClassBadQuery represents errors caused by the query itself, e.g. parse errors.
+	errorClassBadQuery errorClass = "bad_query"
+
+	// errorClassPermanent represents any other error, e.g. a canceled context.
+	errorClassPermanent errorClass = "permanent"
+)
+
+// maxRetryBackoff caps the delay between two attempts of a remote call.
+const maxRetryBackoff = 30 * time.Second
+
+// retryable reports whether a remote call failing with an error of class c may succeed when retried.
+func (c errorClass) retryable() bool {
+	return c == errorClassNetwork || c == errorClassServer
+}
+
+// classifyError returns the class of err, returned by a remote call made with ctx.
+func classifyError(ctx context.Context, err error) errorClass {
+	if ctx.Err() != nil || errors.Is(err, context.Canceled) {
+		return errorClassPermanent
+	}
+	var apiErr *prometheusv1.Error
+	if errors.As(err, &apiErr) {
+		switch apiErr.Type {
+		case prometheusv1.ErrTimeout, prometheusv1.ErrExec, prometheusv1.ErrServer:
+			return errorClassServer
+		case prometheusv1.ErrBadResponse:
+			// usually a proxy in front of Prometheus answering with a non-API body
+			return errorClassServer
+		case prometheusv1.ErrClient:
+			if strings.HasSuffix(apiErr.Msg, "429") {
+				return errorClassServer
+			}
+			return errorClassBadQuery
+		case prometheusv1.ErrBadData:
+			return errorClassBadQuery
+		default:
+			return errorClassPermanent
+		}
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return errorClassNetwork
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, syscall.ECONNRESET) {
+		return errorClassNetwork
+	}
+	return errorClassPermanent
+}
+
+// retrier retries failed remote calls with exponential backoff and jitter,
+// as long as their errors are retryable. A nil retrier calls once.
+type retrier struct {
+	// maxRetries represents the number of retries after the first attempt
+	maxRetries int
+
+	// backoff represents the delay before the first retry, doubled on every further retry
+	backoff time.Duration
+
+	// sleep waits for the given delay unless ctx is done first
+	sleep func(ctx context.Context, d time.Duration) error
+
+	mu      sync.Mutex
+	retries map[errorClass]int
+}
+
+func newRetrier(maxRetries int, backoff time.Duration) *retrier {
+	if maxRetries <= 0 {
+		return nil
+	}
+	return &retrier{
+		maxRetries: maxRetries,
+		backoff:    backoff,
+		sleep:      sleepContext,
+		retries:    map[errorClass]int{},
+	}
+}
+
+// do calls call until it succeeds, fails with an error which is not
+// retryable, or the retries are exhausted. do returns the last error.
+func (r *retrier) do(ctx context.Context, call func() error) error {
+	if r == nil {
+		return call()
+	}
+	for attempt := 0; ; attempt++ {
+		err := call()
+		if err == nil {
+			return nil
+		}
+		class := classifyError(ctx, err)
+		if !class.retryable() || attempt >= r.maxRetries {
+			return err
+		}
+		r.record(class)
+		if err := r.sleep(ctx, r.delay(attempt)); err != nil {
+			return err
+		}
+	}
+}
+
+// delay returns the delay before the retry following the given attempt:
+// exponential backoff with equal jitter, capped at maxRetryBackoff.
+func (r *retrier) delay(attempt int) time.Duration {
+	d := r.backoff
+	for i := 0; i < attempt && d < maxRetryBackoff; i++ {
+		d *= 2
+	}
+	d = min(d, maxRetryBackoff)
+	if d <= 0 {
+		return 0
+	}
+	half := d / 2
+	return half + rand.N(d-half+1)
+}
+
+// record counts a retry of a call which failed with an error of class c.
+func (r *retrier) record(c errorClass) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.retries[c]++
+}
+
+// reset resets the retry counts.
+func (r *retrier) reset() {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	clear(r.retries)
+}
+
+// snapshot returns the retry counts by error class collected since the last reset.
+func (r *retrier) snapshot() map[string]int {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.retries) == 0 {
+		return nil
+	}
+	out := make(map[string]int, len(r.retries))
+	for c, n := range r.retries {
+		out[string(c)] = n
+	}
+	return out
+}
+
+// sleepContext waits for d unless ctx is done first.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
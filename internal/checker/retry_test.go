@@ -0,0 +1,170 @@
+package checker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"syscall"
+	"testing"
+	"time"
+
+	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyError(t *testing.T) {
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  error
+		want errorClass
+	}{
+		{"query timeout", context.Background(), &prometheusv1.Error{Type: prometheusv1.ErrTimeout}, errorClassServer},
+		{"execution error", context.Background(), &prometheusv1.Error{Type: prometheusv1.ErrExec}, errorClassServer},
+		{"5xx response", context.Background(), &prometheusv1.Error{Type: prometheusv1.ErrServer, Msg: "server error: 502"}, errorClassServer},
+		{"not ready", context.Background(), &prometheusv1.Error{Type: prometheusv1.ErrServer, Msg: "server error: 503"}, errorClassServer},
+		{"rate limited", context.Background(), &prometheusv1.Error{Type: prometheusv1.ErrClient, Msg: "client error: 429"}, errorClassServer},
+		{"wrapped 503 from a proxy", context.Background(), fmt.Errorf("failed to query metrics: %w", &prometheusv1.Error{Type: prometheusv1.ErrBadResponse}), errorClassServer},
+		{"bad query", context.Background(), &prometheusv1.Error{Type: prometheusv1.ErrBadData, Msg: "parse error"}, errorClassBadQuery},
+		{"not found", context.Background(), &prometheusv1.Error{Type: prometheusv1.ErrClient, Msg: "client error: 404"}, errorClassBadQuery},
+		{"connection reset", context.Background(), &url.Error{Op: "Post", Err: syscall.ECONNRESET}, errorClassNetwork},
+		{"connection refused", context.Background(), &url.Error{Op: "Post", Err: &net.OpError{Op: "dial", Err: syscall.ECONNREFUSED}}, errorClassNetwork},
+		{"unexpected eof", context.Background(), &url.Error{Op: "Post", Err: io.ErrUnexpectedEOF}, errorClassNetwork},
+		{"client timeout", context.Background(), &url.Error{Op: "Post", Err: timeoutError{}}, errorClassNetwork},
+		{"canceled run", canceled, &url.Error{Op: "Post", Err: syscall.ECONNRESET}, errorClassPermanent},
+		{"unknown", context.Background(), errors.New("boom"), errorClassPermanent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, classifyError(tt.ctx, tt.err))
+		})
+	}
+}
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+// newTestRetrier returns a retrier which records its delays instead of sleeping.
+func newTestRetrier(maxRetries int, delays *[]time.Duration) *retrier {
+	r := newRetrier(maxRetries, 100*time.Millisecond)
+	r.sleep = func(_ context.Context, d time.Duration) error {
+		*delays = append(*delays, d)
+		return nil
+	}
+	return r
+}
+
+func TestRetrier_RetriesTransientErrors(t *testing.T) {
+	var delays []time.Duration
+	r := newTestRetrier(3, &delays)
+	transient := &prometheusv1.Error{Type: prometheusv1.ErrTimeout}
+
+	calls := 0
+	err := r.do(context.Background(), func() error {
+		calls++
+		if calls < 3 {
+			return transient
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, 3, calls)
+	require.Equal(t, map[string]int{"server": 2}, r.snapshot())
+
+	// exponential backoff with equal jitter: [d/2, d] for d = 100ms, 200ms
+	require.Len(t, delays, 2)
+	require.GreaterOrEqual(t, delays[0], 50*time.Millisecond)
+	require.LessOrEqual(t, delays[0], 100*time.Millisecond)
+	require.GreaterOrEqual(t, delays[1], 100*time.Millisecond)
+	require.LessOrEqual(t, delays[1], 200*time.Millisecond)
+}
+
+func TestRetrier_GivesUpAfterMaxRetries(t *testing.T) {
+	var delays []time.Duration
+	r := newTestRetrier(2, &delays)
+	transient := &url.Error{Op: "Post", Err: syscall.ECONNRESET}
+
+	calls := 0
+	err := r.do(context.Background(), func() error {
+		calls++
+		return transient
+	})
+	require.ErrorIs(t, err, syscall.ECONNRESET)
+	require.Equal(t, 3, calls, "one attempt plus two retries")
+	require.Equal(t, map[string]int{"network": 2}, r.snapshot())
+
+	r.reset()
+	require.Nil(t, r.snapshot())
+}
+
+func TestRetrier_DoesNotRetryBadQueries(t *testing.T) {
+	var delays []time.Duration
+	r := newTestRetrier(3, &delays)
+
+	calls := 0
+	err := r.do(context.Background(), func() error {
+		calls++
+		return &prometheusv1.Error{Type: prometheusv1.ErrBadData}
+	})
+	require.Error(t, err)
+	require.Equal(t, 1, calls)
+	require.Empty(t, delays)
+}
+
+func TestRetrier_DelayIsCapped(t *testing.T) {
+	r := newRetrier(100, time.Second)
+	for attempt := range 100 {
+		require.LessOrEqual(t, r.delay(attempt), maxRetryBackoff)
+	}
+	require.GreaterOrEqual(t, r.delay(99), maxRetryBackoff/2)
+}
+
+func TestRetrier_NilCallsOnce(t *testing.T) {
+	require.Nil(t, newRetrier(0, time.Second))
+
+	var r *retrier
+	calls := 0
+	err := r.do(context.Background(), func() error {
+		calls++
+		return &prometheusv1.Error{Type: prometheusv1.ErrTimeout}
+	})
+	require.Error(t, err)
+	require.Equal(t, 1, calls)
+	require.Nil(t, r.snapshot())
+}
+
+// flakyAPI fails the first failures Query calls with a 503 response.
+type flakyAPI struct {
+	fakeAPI
+	failures int
+	calls    int
+}
+
+func (f *flakyAPI) Query(ctx context.Context, query string, ts time.Time, opts ...prometheusv1.Option) (model.Value, prometheusv1.Warnings, error) {
+	f.calls++
+	if f.calls <= f.failures {
+		return nil, nil, &prometheusv1.Error{Type: prometheusv1.ErrServer, Msg: "server error: 503"}
+	}
+	return f.fakeAPI.Query(ctx, query, ts, opts...)
+}
+
+func TestProbe_RetriesTransientQueryErrors(t *testing.T) {
+	var delays []time.Duration
+	api := &flakyAPI{fakeAPI: fakeAPI{value: model.Vector{&model.Sample{Value: 4}}}, failures: 1}
+	p := newPrometheusProbe("", api, newTestRetrier(2, &delays), nil)
+
+	v, err := p.ProbeSelector(context.Background(), `up`, time.Now())
+	require.NoError(t, err)
+	require.Equal(t, float64(4), v)
+	require.Equal(t, 2, api.calls)
+}
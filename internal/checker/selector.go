@@ -0,0 +1,147 @@
+package checker
+
+import (
+	"time"
+
+	promql "github.com/prometheus/prometheus/promql/parser"
+)
+
+// ruleSelector represents a vector selector of a rule expression together
+// with the modifiers it is evaluated with.
+type ruleSelector struct {
+	// expr is the bare vector selector without any modifiers, e.g. foo{job="x"}
+	expr string
+
+	// text is the selector as written in the rule, including its own range,
+	// offset and @ modifiers, e.g. foo{job="x"}[5m] offset 1h
+	text string
+
+	// window is the range of the enclosing matrix selector plus the ranges of
+	// all enclosing subqueries, zero for a plain instant vector selector
+	window time.Duration
+
+	// offset is the selector's offset plus the offsets of all enclosing subqueries
+	offset time.Duration
+
+	// at is the evaluation timestamp pinned by an @ modifier, nil if there is none
+	at *time.Time
+
+	// groupingLabels are the labels grouping and matching clauses of the
+	// expression apply to the selector's series, see groupingLabels
+	groupingLabels []string
+
+	// expectedEmpty reports whether the rule expects the selector to yield no
+	// series at times, see expectedEmpty
+	expectedEmpty bool
+}
+
+// newRuleSelector returns the ruleSelector for vs, given the path of its
+// ancestor nodes in the expression (outermost first).
+// Modifiers of enclosing subqueries only widen and shift the evaluated
+// window, text only shows the selector's own modifiers.
+func newRuleSelector(vs *promql.VectorSelector, path []promql.Node) ruleSelector {
+	bare := promql.VectorSelector{Name: vs.Name, LabelMatchers: vs.LabelMatchers}
+	own := promql.VectorSelector{
+		Name:           vs.Name,
+		LabelMatchers:  vs.LabelMatchers,
+		OriginalOffset: vs.OriginalOffset,
+		Timestamp:      vs.Timestamp,
+		StartOrEnd:     vs.StartOrEnd,
+	}
+	s := ruleSelector{
+		expr:           bare.String(),
+		text:           own.String(),
+		offset:         vs.OriginalOffset,
+		groupingLabels: groupingLabels(vs, path),
+		expectedEmpty:  expectedEmpty(vs, path),
+	}
+
+	if len(path) > 0 {
+		if ms, ok := path[len(path)-1].(*promql.MatrixSelector); ok {
+			s.window = ms.Range
+			s.text = (&promql.MatrixSelector{VectorSelector: &own, Range: ms.Range}).String()
+			path = path[:len(path)-1]
+		}
+	}
+
+	// An @ modifier pins the evaluation timestamp, so enclosing subqueries
+	// don't move it any further.
+	at, pinned := vs.Timestamp, vs.Timestamp != nil || vs.StartOrEnd != 0
+	for i := len(path) - 1; i >= 0 && !pinned; i-- {
+		sq, ok := path[i].(*promql.SubqueryExpr)
+		if !ok {
+			continue
+		}
+		s.window += sq.Range
+		s.offset += sq.OriginalOffset
+		at, pinned = sq.Timestamp, sq.Timestamp != nil || sq.StartOrEnd != 0
+	}
+	if at != nil {
+		t := time.UnixMilli(*at)
+		s.at = &t
+	}
+	return s
+}
+
+// expectedEmpty reports whether vs is expected to yield no series at times,
+// given the path of its ancestor nodes in the expression (outermost first):
+// absent() and absent_over_time() exist to catch their argument yielding
+// nothing, and the right-hand side of unless only ever removes series from
+// the result.
+func expectedEmpty(vs *promql.VectorSelector, path []promql.Node) bool {
+	var child promql.Node = vs
+	for i := len(path) - 1; i >= 0; i-- {
+		switch n := path[i].(type) {
+		case *promql.Call:
+			if n.Func.Name == "absent" || n.Func.Name == "absent_over_time" {
+				return true
+			}
+		case *promql.BinaryExpr:
+			if n.Op == promql.LUNLESS && n.RHS == child {
+				return true
+			}
+		}
+		child = path[i]
+	}
+	return false
+}
+
+// partitionExpectedEmpty splits the given selectors without a result value
+// into the ones the rule expects to yield no series at times, see
+// expectedEmpty, and the others.
+func partitionExpectedEmpty(failed []ruleSelector) (expected, unexpected []ruleSelector) {
+	for _, s := range failed {
+		if s.expectedEmpty {
+			expected = append(expected, s)
+		} else {
+			unexpected = append(unexpected, s)
+		}
+	}
+	return expected, unexpected
+}
+
+// evalTime returns the timestamp the selector is evaluated at, given the
+// rule's evaluation timestamp ts. @ start() and @ end() both resolve to ts,
+// since rules are evaluated as instant queries.
+func (s ruleSelector) evalTime(ts time.Time) time.Time {
+	if s.at != nil {
+		ts = *s.at
+	}
+	return ts.Add(-s.offset)
+}
+
+// withExpr returns a copy of s selecting expr instead, keeping all modifiers.
+func (s ruleSelector) withExpr(expr string) ruleSelector {
+	s.expr = expr
+	s.text = expr
+	return s
+}
+
+// selectorTexts returns the text of each of the given selectors.
+func selectorTexts(selectors []ruleSelector) []string {
+	out := make([]string, 0, len(selectors))
+	for _, s := range selectors {
+		out = append(out, s.text)
+	}
+	return out
+}
@@ -0,0 +1,129 @@
+package checker
+
+import (
+	"testing"
+	"time"
+
+	promql "github.com/prometheus/prometheus/promql/parser"
+	"github.com/stretchr/testify/require"
+)
+
+// instantSelectors returns plain instant vector selectors without modifiers for the given expressions.
+func instantSelectors(exprs ...string) []ruleSelector {
+	out := make([]ruleSelector, 0, len(exprs))
+	for _, expr := range exprs {
+		out = append(out, ruleSelector{expr: expr, text: expr})
+	}
+	return out
+}
+
+func TestGetRuleSelectors_Modifiers(t *testing.T) {
+	at := time.Unix(1700000000, 0)
+	tests := []struct {
+		name string
+		expr string
+		want ruleSelector
+	}{
+		{
+			name: "instant vector selector",
+			expr: `up{job="x"}`,
+			want: ruleSelector{expr: `up{job="x"}`, text: `up{job="x"}`},
+		},
+		{
+			name: "offset",
+			expr: `up offset 1h`,
+			want: ruleSelector{expr: `up`, text: `up offset 1h`, offset: time.Hour},
+		},
+		{
+			name: "matrix selector with offset",
+			expr: `rate(http_requests_total{job="x"}[5m] offset 1h)`,
+			want: ruleSelector{
+				expr:   `http_requests_total{job="x"}`,
+				text:   `http_requests_total{job="x"}[5m] offset 1h`,
+				window: 5 * time.Minute,
+				offset: time.Hour,
+			},
+		},
+		{
+			name: "@ modifier",
+			expr: `up @ 1700000000`,
+			want: ruleSelector{expr: `up`, text: `up @ 1700000000.000`, at: &at},
+		},
+		{
+			name: "subquery widens and shifts the window",
+			expr: `max_over_time(rate(foo[5m])[1h:1m] offset 30m)`,
+			want: ruleSelector{expr: `foo`, text: `foo[5m]`, window: time.Hour + 5*time.Minute, offset: 30 * time.Minute},
+		},
+		{
+			name: "@ pins the selector within a subquery",
+			expr: `max_over_time(rate(foo[5m] @ 1700000000)[1h:1m] offset 30m)`,
+			want: ruleSelector{expr: `foo`, text: `foo[5m] @ 1700000000.000`, window: 5 * time.Minute, at: &at},
+		},
+	}
+	p := promql.NewParser(promql.Options{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getRuleSelectors(p, tt.expr)
+			require.NoError(t, err)
+			require.Equal(t, []ruleSelector{tt.want}, got)
+		})
+	}
+}
+
+func TestCheckRule_HonorsModifiers(t *testing.T) {
+	ts := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	fp := &fakeProber{
+		values:      map[string]float64{`up`: 1},
+		rangeValues: map[string]float64{`http_requests_total`: 1},
+	}
+	prc := &PrometheusRulesChecker{probe: fp, parser: promql.NewParser(promql.Options{})}
+
+	got, err := prc.checkRule(t.Context(), ts, Rule{
+		Name:       "r",
+		Expression: `rate(http_requests_total[5m] offset 1h) > 0 and up offset 1d`,
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{`http_requests_total[5m] offset 1h`, `up offset 1d`}, got.Results)
+	require.Equal(t, 5*time.Minute, fp.windows[`http_requests_total`])
+	require.Equal(t, ts.Add(-time.Hour), fp.rangeTs[`http_requests_total`])
+	require.Equal(t, []time.Time{ts.Add(-24 * time.Hour)}, fp.tsCalls)
+}
+
+func TestGetRuleSelectors_ExpectedEmpty(t *testing.T) {
+	tests := []struct {
+		name string
+		expr string
+		want map[string]bool
+	}{
+		{"absent", `absent(up{job="foo"} == 1)`, map[string]bool{`up{job="foo"}`: true}},
+		{"absent_over_time", `absent_over_time(up{job="foo"}[5m])`, map[string]bool{`up{job="foo"}`: true}},
+		{"unless", `foo unless on (pod) bar`, map[string]bool{`foo`: false, `bar`: true}},
+		{"nested unless", `sum(foo) unless (bar > 0 or baz)`, map[string]bool{`foo`: false, `bar`: true, `baz`: true}},
+		{"absent on the left of unless", `absent(foo) unless bar`, map[string]bool{`foo`: true, `bar`: true}},
+		{"other functions", `rate(foo[5m]) and bar`, map[string]bool{`foo`: false, `bar`: false}},
+	}
+	p := promql.NewParser(promql.Options{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			selectors, err := getRuleSelectors(p, tt.expr)
+			require.NoError(t, err)
+			got := map[string]bool{}
+			for _, s := range selectors {
+				got[s.expr] = s.expectedEmpty
+			}
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCheckRule_ReportsExpectedEmptySelectors(t *testing.T) {
+	fp := &fakeProber{values: map[string]float64{`foo`: 1}}
+	prc := &PrometheusRulesChecker{probe: fp, parser: promql.NewParser(promql.Options{}), drillDown: true}
+
+	got, err := prc.checkRule(t.Context(), time.Now(), Rule{Name: "r", Expression: `(foo unless bar) or absent(baz{job="x"}) or qux`})
+	require.NoError(t, err)
+	require.Equal(t, []string{`foo`}, got.Results)
+	require.Equal(t, []string{`qux`}, got.NoResults)
+	require.Equal(t, []string{`bar`, `baz{job="x"}`}, got.ExpectedEmpty)
+	require.NotContains(t, fp.calls, `baz`, "expected empty selectors are not drilled down")
+}
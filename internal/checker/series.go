@@ -0,0 +1,72 @@
+package checker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+)
+
+const (
+	// ProberQuery probes selectors with count() instant queries.
+	ProberQuery = "query"
+
+	// ProberSeries probes selectors with the series API.
+	ProberSeries = "series"
+)
+
+// defaultLookbackDelta mirrors Prometheus' default query lookback delta: an
+// instant query at ts only sees samples from the 5 minutes before ts.
+const defaultLookbackDelta = 5 * time.Minute
+
+// seriesProbe probes selectors with the /api/v1/series endpoint instead of
+// instant queries. Series lookups only touch the index, which makes them
+// cheaper for pure existence checks, and they take a start/end window anyway.
+// Since the index is organized in blocks, a series may be reported slightly
+// beyond its last sample.
+type seriesProbe struct {
+	api prometheusv1.API
+
+	// retry retries failed series lookups, a nil retry looks up once
+	retry *retrier
+
+	// limit rate limits series lookups, a nil limit does not limit
+	limit *rateLimiter
+
+	// opts represents the options passed with every lookup, e.g. a timeout or series limit
+	opts []prometheusv1.Option
+}
+
+func newSeriesProbe(client prometheusv1.API, retry *retrier, limit *rateLimiter, opts ...prometheusv1.Option) *seriesProbe {
+	return &seriesProbe{api: client, retry: retry, limit: limit, opts: opts}
+}
+
+// ProbeSelector implements Prober.
+func (p *seriesProbe) ProbeSelector(ctx context.Context, selector string, ts time.Time) (float64, error) {
+	return p.series(ctx, selector, ts, defaultLookbackDelta)
+}
+
+// ProbeSelectorRange implements RangeProber.
+func (p *seriesProbe) ProbeSelectorRange(ctx context.Context, selector string, ts time.Time, window time.Duration) (float64, error) {
+	return p.series(ctx, selector, ts, window)
+}
+
+// series returns the number of series matching selector within the window
+// ending at ts. The warnings of the lookup are recorded in the warningCollector of ctx, if any.
+func (p *seriesProbe) series(ctx context.Context, selector string, ts time.Time, window time.Duration) (float64, error) {
+	var (
+		series   []model.LabelSet
+		warnings prometheusv1.Warnings
+	)
+	err := callRemote(ctx, p.retry, p.limit, func() (err error) {
+		series, warnings, err = p.api.Series(ctx, []string{selector}, ts.Add(-window), ts, p.opts...)
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to query series: %w", err)
+	}
+	addWarnings(ctx, warnings)
+	return float64(len(series)), nil
+}
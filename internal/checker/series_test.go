@@ -0,0 +1,100 @@
+package checker
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/api"
+	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSeriesAPI struct {
+	prometheusv1.API // embed so unimplemented methods panic if called
+	series           []model.LabelSet
+
+	// gotMatches, gotStart and gotEnd record the arguments of the last Series call
+	gotMatches []string
+	gotStart   time.Time
+	gotEnd     time.Time
+}
+
+func (f *fakeSeriesAPI) Series(_ context.Context, matches []string, start, end time.Time, _ ...prometheusv1.Option) ([]model.LabelSet, prometheusv1.Warnings, error) {
+	f.gotMatches, f.gotStart, f.gotEnd = matches, start, end
+	return f.series, nil, nil
+}
+
+func TestSeriesProbe_ProbeSelector(t *testing.T) {
+	api := &fakeSeriesAPI{series: []model.LabelSet{{"job": "a"}, {"job": "b"}}}
+	p := newSeriesProbe(api, nil, nil)
+	ts := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	got, err := p.ProbeSelector(context.Background(), `up{job=~"a|b"}`, ts)
+	require.NoError(t, err)
+	require.Equal(t, float64(2), got)
+	require.Equal(t, []string{`up{job=~"a|b"}`}, api.gotMatches)
+	require.Equal(t, ts.Add(-defaultLookbackDelta), api.gotStart)
+	require.Equal(t, ts, api.gotEnd)
+}
+
+func TestSeriesProbe_ProbeSelectorRange(t *testing.T) {
+	api := &fakeSeriesAPI{}
+	p := newSeriesProbe(api, nil, nil)
+	ts := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+
+	got, err := p.ProbeSelectorRange(context.Background(), `batch_job_success`, ts, 24*time.Hour)
+	require.NoError(t, err)
+	require.Zero(t, got)
+	require.Equal(t, ts.Add(-24*time.Hour), api.gotStart)
+}
+
+func TestNewPrometheusRulesChecker_SelectsProber(t *testing.T) {
+	prc, err := NewPrometheusRulesChecker(PrometheusRulesCheckerConfig{}, nil)
+	require.NoError(t, err)
+	require.IsType(t, &prometheusProbe{}, prc.probe, "the query prober must be the default")
+
+	prc, err = NewPrometheusRulesChecker(PrometheusRulesCheckerConfig{Prober: ProberSeries}, nil)
+	require.NoError(t, err)
+	require.IsType(t, &seriesProbe{}, prc.probe)
+	require.IsType(t, &prometheusProbe{}, prc.query, "ad-hoc queries must still use the query API")
+
+	_, err = NewPrometheusRulesChecker(PrometheusRulesCheckerConfig{Prober: ProberSeries, BatchSize: 10}, nil)
+	require.Error(t, err)
+	_, err = NewPrometheusRulesChecker(PrometheusRulesCheckerConfig{Prober: "carrier-pigeon"}, nil)
+	require.Error(t, err)
+}
+
+func TestNewPrometheusRulesChecker_PassesQueryOptions(t *testing.T) {
+	var queries []url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		queries = append(queries, r.Form)
+		if strings.HasSuffix(r.URL.Path, "/series") {
+			_, _ = w.Write([]byte(`{"status":"success","data":[]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"vector","result":[]}}`))
+	}))
+	defer srv.Close()
+	client, err := api.NewClient(api.Config{Address: srv.URL})
+	require.NoError(t, err)
+
+	prc, err := NewPrometheusRulesChecker(PrometheusRulesCheckerConfig{QueryTimeout: 10 * time.Second}, prometheusv1.NewAPI(client))
+	require.NoError(t, err)
+	_, err = prc.probe.ProbeSelector(t.Context(), `up`, time.Now())
+	require.NoError(t, err)
+	require.Equal(t, "10s", queries[0].Get("timeout"))
+
+	prc, err = NewPrometheusRulesChecker(PrometheusRulesCheckerConfig{Prober: ProberSeries, QueryTimeout: 10 * time.Second, SeriesLimit: 500}, prometheusv1.NewAPI(client))
+	require.NoError(t, err)
+	_, err = prc.probe.ProbeSelector(t.Context(), `up`, time.Now())
+	require.NoError(t, err)
+	require.Equal(t, "10s", queries[1].Get("timeout"))
+	require.Equal(t, "500", queries[1].Get("limit"))
+}
@@ -0,0 +1,45 @@
+package checker
+
+import (
+	"context"
+	"time"
+)
+
+// probeLastSeen looks up the newest sample of each selector and flags the
+// ones older than the configured MaxAge (relative to their evaluation
+// timestamp), or without any sample in the window searched, as stale in results.
+// The window searched is the lookback window, but at least twice MaxAge so
+// samples older than MaxAge can be found at all.
+func (prc *PrometheusRulesChecker) probeLastSeen(ctx context.Context, ts time.Time, selectors []ruleSelector, results *selectorResults) error {
+	window := max(prc.lookback, 2*prc.maxAge)
+	seen := make(map[string]struct{}, len(selectors))
+	for _, selector := range selectors {
+		if _, ok := seen[selector.text]; ok {
+			continue
+		}
+		seen[selector.text] = struct{}{}
+
+		evalTime := selector.evalTime(ts)
+		lastSeen, err := prc.lastSeen(ctx, selector.expr, evalTime, window)
+		if err != nil {
+			return err
+		}
+		r := results.get(selector.text)
+		r.LastSeen = lastSeen
+		if lastSeen.IsZero() {
+			r.Stale, r.NotSeenWithin = true, window
+			continue
+		}
+		r.Stale = evalTime.Sub(lastSeen) > prc.maxAge
+	}
+	return nil
+}
+
+// lastSeen queries the newest sample timestamp of a selector, honoring the configured probe concurrency bound.
+func (prc *PrometheusRulesChecker) lastSeen(ctx context.Context, selector string, ts time.Time, window time.Duration) (time.Time, error) {
+	if err := prc.acquire(ctx); err != nil {
+		return time.Time{}, err
+	}
+	defer prc.release()
+	return prc.query.LastSeen(ctx, selector, ts, window)
+}
@@ -0,0 +1,39 @@
+package checker
+
+import (
+	"testing"
+	"time"
+
+	promql "github.com/prometheus/prometheus/promql/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckRule_FlagsStaleSelectors(t *testing.T) {
+	ts := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	fp := &fakeProber{values: map[string]float64{`up`: 1}}
+	fq := &fakeQuerier{lastSeen: map[string]time.Time{
+		`up`:        ts.Add(-30 * time.Second),
+		`batch_run`: ts.Add(-3 * time.Hour),
+	}}
+	prc := &PrometheusRulesChecker{probe: fp, query: fq, parser: promql.NewParser(promql.Options{}), maxAge: time.Hour}
+
+	got, err := prc.checkRule(t.Context(), ts, Rule{Name: "r", Expression: `up or batch_run or never_seen`})
+	require.NoError(t, err)
+	require.Equal(t, []SelectorResult{
+		{Selector: `up`, LastSeen: ts.Add(-30 * time.Second), Series: 1},
+		{Selector: `batch_run`, LastSeen: ts.Add(-3 * time.Hour), Stale: true},
+		{Selector: `never_seen`, Stale: true, NotSeenWithin: 2 * time.Hour},
+	}, got.Selectors)
+	require.Equal(t, 2*time.Hour, fq.lastSeenWindow, "window must be at least twice the max age")
+}
+
+func TestProbeLastSeen_FlagsOlderThanMaxAge(t *testing.T) {
+	ts := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	fq := &fakeQuerier{lastSeen: map[string]time.Time{`batch_run`: ts.Add(-3 * time.Hour)}}
+	prc := &PrometheusRulesChecker{query: fq, maxAge: time.Hour, lookback: 24 * time.Hour}
+
+	results := newSelectorResults()
+	require.NoError(t, prc.probeLastSeen(t.Context(), ts, instantSelectors(`batch_run`, `batch_run`), results))
+	require.Equal(t, []SelectorResult{{Selector: `batch_run`, LastSeen: ts.Add(-3 * time.Hour), Stale: true}}, results.list())
+	require.Equal(t, 24*time.Hour, fq.lastSeenWindow, "window must honor a longer lookback")
+}
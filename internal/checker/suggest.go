@@ -0,0 +1,190 @@
+package checker
+
+import (
+	"cmp"
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/prometheus/model/labels"
+)
+
+// maxSuggestions bounds the number of "did you mean" suggestions per selector.
+const maxSuggestions = 3
+
+// suggestWindow represents the minimum window metric names and label values
+// are looked up in for suggestions. The lookback window takes precedence if
+// it is longer.
+const suggestWindow = time.Hour
+
+// suggest looks up existing metric names or label values close to the
+// missing ones found by the drill-down of a selector, and records them as
+// diagnosis suggestions.
+func (prc *PrometheusRulesChecker) suggest(ctx context.Context, ts time.Time, matchers []*labels.Matcher, diagnosis *Diagnosis) error {
+	name, _ := splitNameMatcher(matchers)
+
+	if diagnosis.MetricMissing {
+		names, err := prc.metricNames(ctx, ts)
+		if err != nil {
+			return err
+		}
+		diagnosis.Suggestions = append(diagnosis.Suggestions, closestMatches(name.Value, names, maxSuggestions)...)
+		return nil
+	}
+
+	var scope []string
+	if name != nil {
+		scope = []string{selectorString([]*labels.Matcher{name})}
+	}
+	for _, m := range matchers {
+		if m.Type != labels.MatchEqual || m == name || !slices.Contains(diagnosis.Culprits, m.String()) {
+			continue
+		}
+		values, err := prc.labelValues(ctx, m.Name, scope, ts)
+		if err != nil {
+			return err
+		}
+		for _, v := range closestMatches(m.Value, values, maxSuggestions) {
+			suggested := labels.MustNewMatcher(labels.MatchEqual, m.Name, v)
+			diagnosis.Suggestions = append(diagnosis.Suggestions, suggested.String())
+		}
+	}
+	return nil
+}
+
+// metricNames returns the metric names with samples in the window ending at
+// ts. Within a run they are looked up once, in the window ending at the
+// run's timestamp, and shared by every selector of the run.
+func (prc *PrometheusRulesChecker) metricNames(ctx context.Context, ts time.Time) ([]string, error) {
+	run := prc.currentRun()
+	if run == nil {
+		return prc.labelValues(ctx, labels.MetricName, nil, ts)
+	}
+	return run.metricNames.get(ctx, func(ctx context.Context) ([]string, error) {
+		return prc.labelValues(ctx, labels.MetricName, nil, run.ts)
+	})
+}
+
+// labelValues queries label values with samples in the suggestion window
+// ending at ts, honoring the configured probe concurrency bound.
+func (prc *PrometheusRulesChecker) labelValues(ctx context.Context, label string, selectors []string, ts time.Time) ([]string, error) {
+	if err := prc.acquire(ctx); err != nil {
+		return nil, err
+	}
+	defer prc.release()
+	return prc.query.LabelValues(ctx, label, selectors, ts.Add(-max(prc.lookback, suggestWindow)), ts)
+}
+
+// metricNameCache caches the metric names of a run. Concurrent lookups
+// share a single in-flight query. Failed lookups are not cached.
+type metricNameCache struct {
+	flights flightGroup
+
+	mu      sync.Mutex
+	names   []string
+	fetched bool
+}
+
+// get returns the cached metric names, or calls fetch to get them.
+func (c *metricNameCache) get(ctx context.Context, fetch func(ctx context.Context) ([]string, error)) ([]string, error) {
+	c.mu.Lock()
+	if c.fetched {
+		names := c.names
+		c.mu.Unlock()
+		return names, nil
+	}
+	c.mu.Unlock()
+
+	v, err, _ := c.flights.do(ctx, labels.MetricName, func(ctx context.Context) (any, error) {
+		// an identical lookup may have completed since the check above
+		c.mu.Lock()
+		fetched, names := c.fetched, c.names
+		c.mu.Unlock()
+		if fetched {
+			return names, nil
+		}
+		names, err := fetch(ctx)
+		if err != nil {
+			return nil, err
+		}
+		c.mu.Lock()
+		c.names, c.fetched = names, true
+		c.mu.Unlock()
+		return names, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]string), nil
+}
+
+// closestMatches returns up to n candidates closest to target by edit
+// distance. Candidates containing target (or contained in it) always qualify,
+// so renames like node_cpu -> node_cpu_seconds_total are found, others only
+// within a distance relative to the target's length. Containment of strings
+// shorter than minContained characters doesn't count. If nothing qualifies but
+// there are at most n candidates, all of them are returned, since listing the
+// few values that do exist is the next best hint.
+func closestMatches(target string, candidates []string, n int) []string {
+	type scored struct {
+		candidate string
+		distance  int
+	}
+	maxDistance := max(2, len(target)/3)
+
+	matches := make([]scored, 0, n)
+	for _, c := range candidates {
+		if c == target {
+			continue
+		}
+		d := levenshtein(target, c)
+		if d > maxDistance && !contains(c, target) && !contains(target, c) {
+			continue
+		}
+		matches = append(matches, scored{candidate: c, distance: d})
+	}
+	if len(matches) == 0 && len(candidates) <= n {
+		return slices.DeleteFunc(slices.Clone(candidates), func(c string) bool { return c == target })
+	}
+	slices.SortFunc(matches, func(a, b scored) int {
+		return cmp.Or(cmp.Compare(a.distance, b.distance), cmp.Compare(a.candidate, b.candidate))
+	})
+
+	out := make([]string, 0, min(n, len(matches)))
+	for _, m := range matches[:min(n, len(matches))] {
+		out = append(out, m.candidate)
+	}
+	return out
+}
+
+// minContained is the minimum length of a substring for closestMatches to consider containment.
+const minContained = 3
+
+// contains reports whether s contains substr of at least minContained characters.
+func contains(s, substr string) bool {
+	return len(substr) >= minContained && strings.Contains(s, substr)
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,154 @@
+package checker
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/common/model"
+	promql "github.com/prometheus/prometheus/promql/parser"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeQuerier is a test helper implementing Querier interface
+type fakeQuerier struct {
+	// values maps a label name to the values LabelValues returns
+	values map[string][]string
+	// selectors records the selectors passed to LabelValues, keyed by label name
+	selectors map[string][]string
+	// labelValuesCalls counts the LabelValues calls, keyed by label name
+	labelValuesCalls map[string]int
+	// start records the start passed to the last LabelValues call
+	start time.Time
+	// lastSeen maps a selector string to the timestamp LastSeen returns
+	lastSeen map[string]time.Time
+	// lastSeenWindow records the window passed to the last LastSeen call
+	lastSeenWindow time.Duration
+	// labelSets maps an expression to the label sets LabelSets returns
+	labelSets map[string][]model.Metric
+	// counts maps an expression to the series count Count returns
+	counts map[string]float64
+	// matrices maps an expression to the series QueryRange returns
+	matrices map[string]model.Matrix
+	// step records the step passed to the last QueryRange call
+	step time.Duration
+}
+
+func (f *fakeQuerier) LabelValues(_ context.Context, label string, selectors []string, start, _ time.Time) ([]string, error) {
+	if f.selectors == nil {
+		f.selectors = map[string][]string{}
+		f.labelValuesCalls = map[string]int{}
+	}
+	f.selectors[label] = selectors
+	f.labelValuesCalls[label]++
+	f.start = start
+	return f.values[label], nil
+}
+
+func (f *fakeQuerier) LastSeen(_ context.Context, selector string, _ time.Time, window time.Duration) (time.Time, error) {
+	f.lastSeenWindow = window
+	return f.lastSeen[selector], nil
+}
+
+func (f *fakeQuerier) LabelSets(_ context.Context, expr string, _ time.Time) ([]model.Metric, error) {
+	return f.labelSets[expr], nil
+}
+
+func (f *fakeQuerier) Count(_ context.Context, expr string, _ time.Time) (float64, error) {
+	return f.counts[expr], nil
+}
+
+func (f *fakeQuerier) QueryRange(_ context.Context, expr string, _, _ time.Time, step time.Duration) (model.Matrix, error) {
+	f.step = step
+	return f.matrices[expr], nil
+}
+
+func Test_closestMatches(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		candidates []string
+		want       []string
+	}{
+		{
+			name:       "must suggest renamed metric containing the old name",
+			target:     "node_cpu",
+			candidates: []string{"node_cpu_seconds_total", "node_memory_MemFree_bytes", "up"},
+			want:       []string{"node_cpu_seconds_total"},
+		},
+		{
+			name:       "must rank by edit distance",
+			target:     "http_requests_total",
+			candidates: []string{"http_request_total", "http_requests_totals", "grpc_requests_total", "process_cpu_seconds_total"},
+			want:       []string{"http_request_total", "http_requests_totals", "grpc_requests_total"},
+		},
+		{
+			name:       "must list the few existing values if none is close",
+			target:     "kube-state-metrics",
+			candidates: []string{"ksm"},
+			want:       []string{"ksm"},
+		},
+		{
+			name:       "must not suggest unrelated values",
+			target:     "kube-state-metrics",
+			candidates: []string{"a", "b", "c", "d"},
+			want:       []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, closestMatches(tt.target, tt.candidates, maxSuggestions))
+		})
+	}
+}
+
+func Test_levenshtein(t *testing.T) {
+	require.Equal(t, 0, levenshtein("up", "up"))
+	require.Equal(t, 3, levenshtein("kitten", "sitting"))
+	require.Equal(t, 14, levenshtein("node_cpu", "node_cpu_seconds_total"))
+}
+
+func TestDrillDown_SuggestsMetricNames(t *testing.T) {
+	fp := &fakeProber{values: map[string]float64{}}
+	fq := &fakeQuerier{values: map[string][]string{"__name__": {"node_cpu_seconds_total", "up"}}}
+	prc := &PrometheusRulesChecker{probe: fp, query: fq, parser: promql.NewParser(promql.Options{}), suggestions: true}
+
+	results := newSelectorResults()
+	err := prc.drillDownSelectors(t.Context(), time.Now(), instantSelectors(`node_cpu{mode="idle"}`), results)
+	require.NoError(t, err)
+	got := results.list()
+	require.Equal(t, &Diagnosis{MetricMissing: true, Suggestions: []string{"node_cpu_seconds_total"}}, got[0].Diagnosis)
+}
+
+func TestDrillDown_LooksUpMetricNamesOncePerRun(t *testing.T) {
+	fp := &fakeProber{values: map[string]float64{}}
+	fq := &fakeQuerier{values: map[string][]string{"__name__": {"node_cpu_seconds_total", "node_memory_MemFree_bytes"}}}
+	prc := &PrometheusRulesChecker{probe: fp, query: fq, parser: promql.NewParser(promql.Options{}), suggestions: true, lookback: 6 * time.Hour}
+	prc.StartRun()
+	ts := prc.currentRun().ts
+
+	results := newSelectorResults()
+	err := prc.drillDownSelectors(t.Context(), ts, instantSelectors(`node_cpu`, `node_memory_MemFree`), results)
+	require.NoError(t, err)
+	got := results.list()
+	require.Equal(t, []string{"node_cpu_seconds_total"}, got[0].Diagnosis.Suggestions)
+	require.Equal(t, []string{"node_memory_MemFree_bytes"}, got[1].Diagnosis.Suggestions)
+	require.Equal(t, 1, fq.labelValuesCalls["__name__"], "metric names must be looked up once per run")
+	require.Equal(t, ts.Add(-6*time.Hour), fq.start, "metric names must be looked up in the lookback window")
+}
+
+func TestDrillDown_SuggestsLabelValuesOfCulprit(t *testing.T) {
+	fp := &fakeProber{values: map[string]float64{`kube_pod_info`: 1}}
+	fq := &fakeQuerier{values: map[string][]string{"job": {"kube-state-metric"}}}
+	prc := &PrometheusRulesChecker{probe: fp, query: fq, parser: promql.NewParser(promql.Options{}), suggestions: true}
+
+	results := newSelectorResults()
+	err := prc.drillDownSelectors(t.Context(), time.Now(), instantSelectors(`kube_pod_info{job="kube-state-metrics"}`), results)
+	require.NoError(t, err)
+	got := results.list()
+	require.Equal(t, &Diagnosis{
+		Culprits:    []string{`job="kube-state-metrics"`},
+		Suggestions: []string{`job="kube-state-metric"`},
+	}, got[0].Diagnosis)
+	require.Equal(t, []string{`kube_pod_info`}, fq.selectors["job"], "label values must be scoped to the metric")
+}
@@ -0,0 +1,129 @@
+package checker
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// SuppressionAnnotation represents the rule annotation holding suppression
+// directives, one per line, see ParseSuppression.
+const SuppressionAnnotation = "promcheck.io/ignore"
+
+// Suppression silences the findings of a rule's selectors without a result value.
+type Suppression struct {
+	// Selector represents the pattern of the selectors whose findings are
+	// suppressed, matched against selectors without modifiers. A nil
+	// Selector suppresses the findings of every selector of the rule.
+	Selector *regexp.Regexp
+
+	// Expires represents the time the suppression stops applying at, zero if it never expires
+	Expires time.Time
+
+	// Reason represents why the findings are suppressed
+	Reason string
+}
+
+// SuppressedSelector represents a selector without a result value whose finding is suppressed.
+type SuppressedSelector struct {
+	// Selector represents the suppressed PromQL selector
+	Selector string
+
+	// Expires represents the time the suppression stops applying at, zero if it never expires
+	Expires time.Time
+
+	// Reason represents why the finding is suppressed
+	Reason string
+}
+
+// ParseSuppression parses a suppression directive of the form
+// "[<selector regexp>] [until=<date>] [reason=<text>]". The suppression
+// expires at the date, either YYYY-MM-DD (midnight UTC) or an RFC 3339
+// timestamp. The reason extends to the end of the directive.
+func ParseSuppression(directive string) (Suppression, error) {
+	var s Suppression
+	directive, reason, _ := strings.Cut(directive, "reason=")
+	s.Reason = strings.Trim(strings.TrimSpace(reason), `"'`)
+
+	for _, field := range strings.Fields(directive) {
+		if until, ok := strings.CutPrefix(field, "until="); ok {
+			expires, err := parseExpiry(until)
+			if err != nil {
+				return Suppression{}, err
+			}
+			s.Expires = expires
+			continue
+		}
+		if s.Selector != nil {
+			return Suppression{}, fmt.Errorf("unexpected %q after selector pattern %q", field, s.Selector)
+		}
+		re, err := regexp.Compile(field)
+		if err != nil {
+			return Suppression{}, fmt.Errorf("%q: %w", field, err)
+		}
+		s.Selector = re
+	}
+	return s, nil
+}
+
+// parseExpiry parses the date of an until= field.
+func parseExpiry(until string) (time.Time, error) {
+	if day, err := time.Parse(time.DateOnly, until); err == nil {
+		return day, nil
+	}
+	expires, err := time.Parse(time.RFC3339, until)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("until=%s: expected YYYY-MM-DD or an RFC 3339 timestamp", until)
+	}
+	return expires, nil
+}
+
+// suppressions returns the suppressions of the rule, those of its
+// SuppressionAnnotation followed by its Suppressions.
+func (r Rule) suppressions() ([]Suppression, error) {
+	var out []Suppression
+	for line := range strings.Lines(r.Annotations[SuppressionAnnotation]) {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		s, err := ParseSuppression(line)
+		if err != nil {
+			return nil, fmt.Errorf("annotation %s: %w", SuppressionAnnotation, err)
+		}
+		out = append(out, s)
+	}
+	return append(out, r.Suppressions...), nil
+}
+
+// active reports whether the suppression applies at timestamp ts.
+func (s Suppression) active(ts time.Time) bool {
+	return s.Expires.IsZero() || ts.Before(s.Expires)
+}
+
+// matches reports whether the suppression applies to the given selector.
+func (s Suppression) matches(selector string) bool {
+	return s.Selector == nil || s.Selector.MatchString(selector)
+}
+
+// partitionSuppressed splits failed into the selectors whose findings are
+// suppressed by any of the suppressions active at timestamp ts, and the others.
+func partitionSuppressed(failed []ruleSelector, suppressions []Suppression, ts time.Time) (suppressed []SuppressedSelector, unsuppressed []ruleSelector) {
+	if len(suppressions) == 0 {
+		return nil, failed
+	}
+	for _, sel := range failed {
+		i := slices.IndexFunc(suppressions, func(s Suppression) bool { return s.active(ts) && s.matches(sel.expr) })
+		if i < 0 {
+			unsuppressed = append(unsuppressed, sel)
+			continue
+		}
+		suppressed = append(suppressed, SuppressedSelector{
+			Selector: sel.text,
+			Expires:  suppressions[i].Expires,
+			Reason:   suppressions[i].Reason,
+		})
+	}
+	return suppressed, unsuppressed
+}
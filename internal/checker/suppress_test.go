@@ -0,0 +1,90 @@
+package checker
+
+import (
+	"testing"
+	"time"
+
+	promql "github.com/prometheus/prometheus/promql/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSuppression(t *testing.T) {
+	tests := []struct {
+		name      string
+		directive string
+		selector  string
+		expires   time.Time
+		reason    string
+		wantErr   bool
+	}{
+		{name: "every selector", directive: ""},
+		{name: "selector pattern", directive: "foo_total", selector: "foo_total"},
+		{
+			name:      "expiry date and reason",
+			directive: `kube_.* until=2026-12-31 reason="exporter migration"`,
+			selector:  "kube_.*",
+			expires:   time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC),
+			reason:    "exporter migration",
+		},
+		{
+			name:      "expiry timestamp without selector",
+			directive: "until=2026-11-01T12:00:00Z",
+			expires:   time.Date(2026, 11, 1, 12, 0, 0, 0, time.UTC),
+		},
+		{name: "reason only", directive: "reason=dashboard only", reason: "dashboard only"},
+		{name: "invalid expiry", directive: "foo until=tomorrow", wantErr: true},
+		{name: "invalid pattern", directive: "foo(", wantErr: true},
+		{name: "second pattern", directive: "foo bar", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSuppression(tt.directive)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			if tt.selector == "" {
+				require.Nil(t, got.Selector)
+			} else {
+				require.Equal(t, tt.selector, got.Selector.String())
+			}
+			require.True(t, tt.expires.Equal(got.Expires), "expires %s, got %s", tt.expires, got.Expires)
+			require.Equal(t, tt.reason, got.Reason)
+		})
+	}
+}
+
+func TestCheckRule_SuppressesFindings(t *testing.T) {
+	fp := &fakeProber{values: map[string]float64{`up`: 1}}
+	prc := &PrometheusRulesChecker{probe: fp, parser: promql.NewParser(promql.Options{}), drillDown: true}
+	ts := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	expired, err := ParseSuppression("qux until=2026-10-16")
+	require.NoError(t, err)
+	rule := Rule{
+		Name:         "r",
+		Expression:   `up and foo_total and rate(bar{job="x"}[5m]) and qux`,
+		Annotations:  map[string]string{SuppressionAnnotation: "foo_.*\nbar until=2026-12-31 reason=migration\n"},
+		Suppressions: []Suppression{expired},
+	}
+	got, err := prc.checkRule(t.Context(), ts, rule)
+	require.NoError(t, err)
+	require.Equal(t, []string{`up`}, got.Results)
+	require.Equal(t, []string{`qux`}, got.NoResults, "expired suppressions no longer apply")
+	require.Equal(t, []SuppressedSelector{
+		{Selector: `foo_total`},
+		{Selector: `bar{job="x"}[5m]`, Expires: time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC), Reason: "migration"},
+	}, got.Suppressed)
+	require.NotContains(t, fp.calls, `bar`, "suppressed selectors are not drilled down")
+}
+
+func TestCheckRule_InvalidSuppressionAnnotation(t *testing.T) {
+	prc := &PrometheusRulesChecker{probe: &fakeProber{}, parser: promql.NewParser(promql.Options{})}
+	_, err := prc.checkRule(t.Context(), time.Now(), Rule{
+		Name:        "r",
+		Expression:  `up`,
+		Annotations: map[string]string{SuppressionAnnotation: "up until=soon"},
+	})
+	require.ErrorContains(t, err, "suppression: annotation promcheck.io/ignore")
+}
@@ -0,0 +1,63 @@
+package checker
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// promqlAnnotationPrefixes represents the prefixes of the warnings Prometheus
+// annotates query results with, e.g. "PromQL info: metric might not be a counter".
+var promqlAnnotationPrefixes = []string{"PromQL info:", "PromQL warning:"}
+
+// IsPartialResponse reports whether the given query warning tells the result
+// may be incomplete, e.g. because Thanos or Mimir could not reach some of
+// their stores, as opposed to a PromQL annotation of an otherwise complete result.
+func IsPartialResponse(warning string) bool {
+	return !slices.ContainsFunc(promqlAnnotationPrefixes, func(prefix string) bool {
+		return strings.HasPrefix(warning, prefix)
+	})
+}
+
+// warningsKey is the context key of a warningCollector.
+type warningsKey struct{}
+
+// warningCollector collects the warnings Prometheus returns along with the
+// results of the remote calls made with a context, see withWarnings.
+type warningCollector struct {
+	mu       sync.Mutex
+	warnings []string
+}
+
+// withWarnings returns a copy of ctx collecting the warnings of all remote
+// calls made with it in the returned warningCollector.
+func withWarnings(ctx context.Context) (context.Context, *warningCollector) {
+	c := &warningCollector{}
+	return context.WithValue(ctx, warningsKey{}, c), c
+}
+
+// addWarnings records warnings in the warningCollector of ctx, if any.
+func addWarnings(ctx context.Context, warnings []string) {
+	if len(warnings) == 0 {
+		return
+	}
+	c, ok := ctx.Value(warningsKey{}).(*warningCollector)
+	if !ok {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, w := range warnings {
+		if !slices.Contains(c.warnings, w) {
+			c.warnings = append(c.warnings, w)
+		}
+	}
+}
+
+// list returns the collected warnings in the order they were first seen, nil if there are none.
+func (c *warningCollector) list() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.warnings)
+}
@@ -0,0 +1,91 @@
+package checker
+
+import (
+	"context"
+	"testing"
+
+	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	promql "github.com/prometheus/prometheus/promql/parser"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsPartialResponse(t *testing.T) {
+	tests := []struct {
+		warning string
+		want    bool
+	}{
+		{`PromQL info: metric might not be a counter, name does not end in _total/_sum/_count/_bucket: "up"`, false},
+		{`PromQL warning: encountered a mix of histograms and floats for metric name "x"`, false},
+		{`receive series from Addr: store-1:10901: rpc error: code = Unavailable`, true},
+		{`partial response: 1 of 3 store gateways failed`, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.warning, func(t *testing.T) {
+			require.Equal(t, tt.want, IsPartialResponse(tt.warning))
+		})
+	}
+}
+
+func TestWarningCollector_DeduplicatesWarnings(t *testing.T) {
+	addWarnings(context.Background(), []string{"dropped"})
+
+	ctx, warnings := withWarnings(context.Background())
+	addWarnings(ctx, []string{"a", "b"})
+	addWarnings(ctx, []string{"b", "c"})
+	require.Equal(t, []string{"a", "b", "c"}, warnings.list())
+
+	_, empty := withWarnings(context.Background())
+	require.Nil(t, empty.list())
+}
+
+func TestCheckRule_CarriesQueryWarnings(t *testing.T) {
+	partial := "receive series from Addr: store-1:10901: rpc error: code = Unavailable"
+	api := &fakeAPI{
+		value:    model.Vector{&model.Sample{Value: 1}},
+		warnings: prometheusv1.Warnings{partial},
+	}
+	prc := &PrometheusRulesChecker{probe: newPrometheusProbe("", api, nil, nil), parser: promql.NewParser(promql.Options{})}
+	prc.StartRun()
+	ts := prc.currentRun().ts
+
+	for _, name := range []string{"queried", "cached"} {
+		got, err := prc.checkRule(t.Context(), ts, Rule{Name: name, Expression: `up`})
+		require.NoError(t, err)
+		require.Equal(t, []SelectorResult{{
+			Selector:        `up`,
+			Series:          1,
+			Warnings:        []string{partial},
+			PartialResponse: true,
+		}}, got.Selectors, "%s probes must carry the query's warnings", name)
+	}
+	require.Equal(t, 1, prc.ProbeStats().Hits)
+}
+
+func TestPrefetchBatches_CarriesQueryWarnings(t *testing.T) {
+	annotation := `PromQL info: metric might not be a counter, name does not end in _total/_sum/_count/_bucket: "up"`
+	api := &fakeAPI{
+		value: model.Vector{
+			&model.Sample{Metric: model.Metric{batchLabel: "0"}, Value: 1},
+			&model.Sample{Metric: model.Metric{batchLabel: "1"}, Value: 2},
+		},
+		warnings: prometheusv1.Warnings{annotation},
+	}
+	prc := &PrometheusRulesChecker{
+		probe:  &batchProbe{prometheusProbe: newPrometheusProbe("", api, nil, nil), size: 10},
+		parser: promql.NewParser(promql.Options{}),
+	}
+	prc.StartRun()
+	ts := prc.currentRun().ts
+
+	rule := Rule{Name: "r", Expression: `rate(up[5m]) / down`}
+	require.NoError(t, prc.prefetchBatches(t.Context(), ts, []Rule{rule}))
+	got, err := prc.checkRule(t.Context(), ts, rule)
+	require.NoError(t, err)
+	for _, s := range got.Selectors {
+		require.Equal(t, []string{annotation}, s.Warnings, "every selector of a batch carries the batch query's warnings")
+		require.False(t, s.PartialResponse)
+	}
+	require.Len(t, got.Selectors, 2)
+	require.Equal(t, ProbeStats{Hits: 2, Batches: 1}, prc.ProbeStats())
+}
@@ -43,11 +43,18 @@ func DefaultOptions() Options {
 type Metrics interface {
 	SetRuleGroupsTotal(value float64)
 	SetRulesTotal(value float64)
+	SetRulesErrored(value float64)
+	SetRulesUnchecked(value float64)
 	SetSelectorsTotal(file, group, rule, status string, value float64)
+	SetSelectorSeries(file, group, rule, selector string, value float64)
+	SetSelectorLastSeen(file, group, rule, selector string, t time.Time)
+	SetAlertBacktest(file, group, rule string, firings, firingRatio float64)
 	SetBuildInfo(version, revision, goversion string)
 	SetLastRunTimestamp(t time.Time)
 	SetRunDuration(d time.Duration)
 	IncRunErrors()
+	AddProbeCacheLookups(result string, value float64)
+	AddProbeRetries(class string, value float64)
 	RegisterHandler(path string, handler *http.ServeMux)
 }
 
@@ -19,12 +19,20 @@ const (
 type Prometheus struct {
 	ruleGroupsGaugeM *prometheus.GaugeVec
 	rulesGaugeM      *prometheus.GaugeVec
+	rulesErroredM    prometheus.Gauge
+	rulesUncheckedM  prometheus.Gauge
 	selectorsGaugeM  *prometheus.GaugeVec
+	seriesGaugeM     *prometheus.GaugeVec
+	lastSeenGaugeM   *prometheus.GaugeVec
+	firingsGaugeM    *prometheus.GaugeVec
+	firingRatioM     *prometheus.GaugeVec
 
 	buildInfoGaugeM   *prometheus.GaugeVec
 	lastRunTimestampM prometheus.Gauge
 	runDurationM      prometheus.Gauge
 	runErrorsTotalM   prometheus.Counter
+	probeCacheM       *prometheus.CounterVec
+	probeRetriesM     *prometheus.CounterVec
 
 	opts     Options
 	registry *prometheus.Registry
@@ -56,6 +64,20 @@ func NewPrometheus(opts Options) *Prometheus {
 		Help:      "Total number of evaluated rules.",
 	}, []string{})
 
+	rulesErrored := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: promChecksSubsystem,
+		Name:      "rules_errored_total",
+		Help:      "Total number of rules which could not be checked because of a query or parse error.",
+	})
+
+	rulesUnchecked := prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: promChecksSubsystem,
+		Name:      "rules_unchecked_total",
+		Help:      "Total number of rules which could not be checked before the run's deadline.",
+	})
+
 	selectorsTotal := prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: namespace,
 		Subsystem: promChecksSubsystem,
@@ -63,6 +85,34 @@ func NewPrometheus(opts Options) *Prometheus {
 		Help:      "Total number of evaluated selectors.",
 	}, []string{"file", "group", "rule", "status"})
 
+	selectorSeries := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: promChecksSubsystem,
+		Name:      "selector_series",
+		Help:      "Number of series an evaluated selector yielded.",
+	}, []string{"file", "group", "rule", "selector"})
+
+	selectorLastSeen := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: promChecksSubsystem,
+		Name:      "selector_last_seen_timestamp_seconds",
+		Help:      "Unix timestamp of the newest sample of an evaluated selector.",
+	}, []string{"file", "group", "rule", "selector"})
+
+	backtestFirings := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: promChecksSubsystem,
+		Name:      "alert_backtest_firings",
+		Help:      "Number of times a backtested alerting rule would have fired.",
+	}, []string{"file", "group", "rule"})
+
+	backtestFiringRatio := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Subsystem: promChecksSubsystem,
+		Name:      "alert_backtest_firing_ratio",
+		Help:      "Share of the backtest range a backtested alerting rule would have been firing for.",
+	}, []string{"file", "group", "rule"})
+
 	buildInfo := prometheus.NewGaugeVec(prometheus.GaugeOpts{
 		Namespace: namespace,
 		Name:      "build_info",
@@ -87,14 +137,34 @@ func NewPrometheus(opts Options) *Prometheus {
 		Help:      "Total number of check cycles that returned an error.",
 	})
 
+	probeCacheLookups := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "probe_cache_lookups_total",
+		Help:      "Total number of selector probes by probe cache result (hit, shared or miss).",
+	}, []string{"result"})
+
+	probeRetries := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "probe_retries_total",
+		Help:      "Total number of retried queries by the class of the error they were retried after (network or server).",
+	}, []string{"class"})
+
 	p := &Prometheus{
 		ruleGroupsGaugeM:  ruleGroupsTotal,
 		rulesGaugeM:       rulesTotal,
+		rulesErroredM:     rulesErrored,
+		rulesUncheckedM:   rulesUnchecked,
 		selectorsGaugeM:   selectorsTotal,
+		seriesGaugeM:      selectorSeries,
+		lastSeenGaugeM:    selectorLastSeen,
+		firingsGaugeM:     backtestFirings,
+		firingRatioM:      backtestFiringRatio,
 		buildInfoGaugeM:   buildInfo,
 		lastRunTimestampM: lastRunTimestamp,
 		runDurationM:      runDuration,
 		runErrorsTotalM:   runErrorsTotal,
+		probeCacheM:       probeCacheLookups,
+		probeRetriesM:     probeRetries,
 		opts:              opts,
 		registry:          opts.PrometheusRegistry,
 		handler:           nil,
@@ -110,10 +180,18 @@ func NewPrometheus(opts Options) *Prometheus {
 func (p *Prometheus) registerMetrics() {
 	p.registry.MustRegister(p.ruleGroupsGaugeM)
 	p.registry.MustRegister(p.rulesGaugeM)
+	p.registry.MustRegister(p.rulesErroredM)
+	p.registry.MustRegister(p.rulesUncheckedM)
 	p.registry.MustRegister(p.selectorsGaugeM)
+	p.registry.MustRegister(p.seriesGaugeM)
+	p.registry.MustRegister(p.lastSeenGaugeM)
+	p.registry.MustRegister(p.firingsGaugeM)
+	p.registry.MustRegister(p.firingRatioM)
 	p.registry.MustRegister(p.buildInfoGaugeM)
 	p.registry.MustRegister(p.lastRunTimestampM)
 	p.registry.MustRegister(p.runDurationM)
+	p.registry.MustRegister(p.probeCacheM)
+	p.registry.MustRegister(p.probeRetriesM)
 	p.registry.MustRegister(p.runErrorsTotalM)
 
 	if p.opts.EnableRuntimeMetrics {
@@ -148,10 +226,31 @@ func (p *Prometheus) SetRulesTotal(value float64) {
 	p.rulesGaugeM.WithLabelValues().Set(value)
 }
 
+func (p *Prometheus) SetRulesErrored(value float64) {
+	p.rulesErroredM.Set(value)
+}
+
+func (p *Prometheus) SetRulesUnchecked(value float64) {
+	p.rulesUncheckedM.Set(value)
+}
+
 func (p *Prometheus) SetSelectorsTotal(file, group, rule, status string, value float64) {
 	p.selectorsGaugeM.WithLabelValues(file, group, rule, status).Set(value)
 }
 
+func (p *Prometheus) SetSelectorSeries(file, group, rule, selector string, value float64) {
+	p.seriesGaugeM.WithLabelValues(file, group, rule, selector).Set(value)
+}
+
+func (p *Prometheus) SetSelectorLastSeen(file, group, rule, selector string, t time.Time) {
+	p.lastSeenGaugeM.WithLabelValues(file, group, rule, selector).Set(float64(t.Unix()))
+}
+
+func (p *Prometheus) SetAlertBacktest(file, group, rule string, firings, firingRatio float64) {
+	p.firingsGaugeM.WithLabelValues(file, group, rule).Set(firings)
+	p.firingRatioM.WithLabelValues(file, group, rule).Set(firingRatio)
+}
+
 func (p *Prometheus) SetBuildInfo(version, revision, goversion string) {
 	p.buildInfoGaugeM.WithLabelValues(version, revision, goversion).Set(1)
 }
@@ -167,3 +266,11 @@ func (p *Prometheus) SetRunDuration(d time.Duration) {
 func (p *Prometheus) IncRunErrors() {
 	p.runErrorsTotalM.Inc()
 }
+
+func (p *Prometheus) AddProbeCacheLookups(result string, value float64) {
+	p.probeCacheM.WithLabelValues(result).Add(value)
+}
+
+func (p *Prometheus) AddProbeRetries(class string, value float64) {
+	p.probeRetriesM.WithLabelValues(class).Add(value)
+}
@@ -44,6 +44,26 @@ func TestPrometheus_SetRunDuration(t *testing.T) {
 	}
 }
 
+func TestPrometheus_SetRulesErrored(t *testing.T) {
+	p := NewPrometheus(DefaultOptions())
+
+	p.SetRulesErrored(3)
+
+	if got := testutil.ToFloat64(p.rulesErroredM); got != 3 {
+		t.Fatalf("expected 3, got %v", got)
+	}
+}
+
+func TestPrometheus_SetRulesUnchecked(t *testing.T) {
+	p := NewPrometheus(DefaultOptions())
+
+	p.SetRulesUnchecked(4)
+
+	if got := testutil.ToFloat64(p.rulesUncheckedM); got != 4 {
+		t.Fatalf("expected 4, got %v", got)
+	}
+}
+
 func TestPrometheus_IncRunErrors(t *testing.T) {
 	p := NewPrometheus(DefaultOptions())
 
@@ -102,3 +122,63 @@ myprefix_run_errors_total 1
 		t.Fatalf("unexpected collecting result:\n%s", err)
 	}
 }
+
+func TestPrometheus_SetSelectorLastSeen(t *testing.T) {
+	p := NewPrometheus(DefaultOptions())
+
+	seen := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	p.SetSelectorLastSeen("f.yaml", "g", "r", `up{job="x"}`, seen)
+
+	if got := testutil.ToFloat64(p.lastSeenGaugeM.WithLabelValues("f.yaml", "g", "r", `up{job="x"}`)); got != float64(seen.Unix()) {
+		t.Fatalf("expected %v, got %v", float64(seen.Unix()), got)
+	}
+}
+
+func TestPrometheus_SetSelectorSeries(t *testing.T) {
+	p := NewPrometheus(DefaultOptions())
+
+	p.SetSelectorSeries("f.yaml", "g", "r", `kube_pod_info`, 1423)
+
+	if got := testutil.ToFloat64(p.seriesGaugeM.WithLabelValues("f.yaml", "g", "r", `kube_pod_info`)); got != 1423 {
+		t.Fatalf("expected 1423, got %v", got)
+	}
+}
+
+func TestPrometheus_AddProbeCacheLookups(t *testing.T) {
+	p := NewPrometheus(DefaultOptions())
+
+	p.AddProbeCacheLookups("hit", 3)
+	p.AddProbeCacheLookups("hit", 2)
+	p.AddProbeCacheLookups("miss", 1)
+
+	if got := testutil.ToFloat64(p.probeCacheM.WithLabelValues("hit")); got != 5 {
+		t.Fatalf("expected 5, got %v", got)
+	}
+	if got := testutil.ToFloat64(p.probeCacheM.WithLabelValues("miss")); got != 1 {
+		t.Fatalf("expected 1, got %v", got)
+	}
+}
+
+func TestPrometheus_AddProbeRetries(t *testing.T) {
+	p := NewPrometheus(DefaultOptions())
+
+	p.AddProbeRetries("network", 2)
+	p.AddProbeRetries("network", 1)
+
+	if got := testutil.ToFloat64(p.probeRetriesM.WithLabelValues("network")); got != 3 {
+		t.Fatalf("expected 3, got %v", got)
+	}
+}
+
+func TestPrometheus_SetAlertBacktest(t *testing.T) {
+	p := NewPrometheus(DefaultOptions())
+
+	p.SetAlertBacktest("f.yaml", "g", "InstanceDown", 3, 0.25)
+
+	if got := testutil.ToFloat64(p.firingsGaugeM.WithLabelValues("f.yaml", "g", "InstanceDown")); got != 3 {
+		t.Fatalf("expected 3, got %v", got)
+	}
+	if got := testutil.ToFloat64(p.firingRatioM.WithLabelValues("f.yaml", "g", "InstanceDown")); got != 0.25 {
+		t.Fatalf("expected 0.25, got %v", got)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"io"
 	"os"
 	"slices"
+	"time"
 
 	"github.com/mattn/go-isatty"
 	"gopkg.in/yaml.v3"
@@ -51,8 +52,9 @@ type Builder struct {
 	// so NewBuilder knows not to overwrite it with its own auto-detection.
 	colorExplicit bool
 
-	// onlyFailing restricts rendered sections (tree/json/yaml) to ones with
-	// at least one selector without a result. Summary totals are unaffected.
+	// onlyFailing restricts rendered sections (tree/json/yaml) to ones with at
+	// least one selector without a result, a rule error or an unchecked rule.
+	// Summary totals are unaffected.
 	onlyFailing bool
 }
 
@@ -111,8 +113,9 @@ func WithColor(enabled bool) BuilderOption {
 }
 
 // WithOnlyFailing restricts rendered output (tree, json, yaml) to sections
-// that have at least one selector without a result. Summary totals (groups,
-// rules, selectors, ratio) continue to reflect the full run.
+// that have at least one selector without a result, a rule error or an
+// unchecked rule. Summary totals (groups, rules, selectors, ratio) continue to
+// reflect the full run.
 func WithOnlyFailing() BuilderOption {
 	return func(b *Builder) {
 		b.onlyFailing = true
@@ -153,6 +156,97 @@ type Report struct {
 
 	// RatioFailedTotal represents the ratio of selectors without a result value / total amount of selectors
 	RatioFailedTotal float32 `json:"ratio_failed_total" yaml:"ratio_failed_total"`
+
+	// TotalSelectorsExpectedEmpty represents the total amount of probed selectors without a result value which the rule expects to be empty at times.
+	// These are included in neither TotalSelectorsFailed nor TotalSelectorsSuccess.
+	TotalSelectorsExpectedEmpty int `json:"selectors_expected_empty_total,omitempty" yaml:"selectors_expected_empty_total,omitempty"`
+
+	// TotalSelectorsSuppressed represents the total amount of probed selectors without a result value whose findings are suppressed by their rule.
+	// These are included in neither TotalSelectorsFailed nor TotalSelectorsSuccess.
+	TotalSelectorsSuppressed int `json:"selectors_suppressed_total,omitempty" yaml:"selectors_suppressed_total,omitempty"`
+
+	// TotalSelectorsWindowOnly represents the total amount of probed selectors containing a result value
+	// within the lookback window only. These are included in TotalSelectorsSuccess.
+	TotalSelectorsWindowOnly int `json:"selectors_window_only_total,omitempty" yaml:"selectors_window_only_total,omitempty"`
+
+	// TotalSelectorsNotRecorded represents the total amount of probed selectors referring to a recording rule of the run
+	// which didn't record any series yet, while the recording rule's source selectors all contain a result value.
+	// These are included in TotalSelectorsSuccess.
+	TotalSelectorsNotRecorded int `json:"selectors_not_recorded_total,omitempty" yaml:"selectors_not_recorded_total,omitempty"`
+
+	// TotalSelectorsWithDependencyChains represents the total amount of probed selectors without a result value referring
+	// to a recording rule of the run whose source selectors don't all contain a result value
+	TotalSelectorsWithDependencyChains int `json:"selectors_dependency_chains_total,omitempty" yaml:"selectors_dependency_chains_total,omitempty"`
+
+	// TotalSelectorsStale represents the total amount of probed selectors whose newest sample is older than the max age
+	TotalSelectorsStale int `json:"selectors_stale_total,omitempty" yaml:"selectors_stale_total,omitempty"`
+
+	// TotalSelectorsOverSeriesLimit represents the total amount of probed selectors exceeding their cardinality threshold
+	TotalSelectorsOverSeriesLimit int `json:"selectors_over_series_limit_total,omitempty" yaml:"selectors_over_series_limit_total,omitempty"`
+
+	// TotalRulesErrored represents the total amount of rules which could not be checked because of an error
+	TotalRulesErrored int `json:"rules_errored_total,omitempty" yaml:"rules_errored_total,omitempty"`
+
+	// TotalRulesUnchecked represents the total amount of rules which could not be checked before the run's deadline
+	TotalRulesUnchecked int `json:"rules_unchecked_total,omitempty" yaml:"rules_unchecked_total,omitempty"`
+
+	// TotalRulesWithResults represents the total amount of evaluated rules whose expression yields series
+	TotalRulesWithResults int `json:"rules_with_results_total,omitempty" yaml:"rules_with_results_total,omitempty"`
+
+	// TotalRulesEmpty represents the total amount of evaluated rules whose expression yields no series, although all of its selectors return a result value
+	TotalRulesEmpty int `json:"rules_empty_total,omitempty" yaml:"rules_empty_total,omitempty"`
+
+	// TotalRulesBroken represents the total amount of evaluated rules whose expression yields no series, because selectors return no result value or joins never match
+	TotalRulesBroken int `json:"rules_broken_total,omitempty" yaml:"rules_broken_total,omitempty"`
+
+	// TotalAlertsBacktested represents the total amount of backtested alerting rules
+	TotalAlertsBacktested int `json:"alerts_backtested_total,omitempty" yaml:"alerts_backtested_total,omitempty"`
+
+	// TotalAlertsNeverFired represents the total amount of backtested alerting rules which would never have fired
+	TotalAlertsNeverFired int `json:"alerts_never_fired_total,omitempty" yaml:"alerts_never_fired_total,omitempty"`
+
+	// TotalAlertsFiringContinuously represents the total amount of backtested alerting rules which would have fired nearly continuously
+	TotalAlertsFiringContinuously int `json:"alerts_firing_continuously_total,omitempty" yaml:"alerts_firing_continuously_total,omitempty"`
+
+	// TotalEmptyJoins represents the total amount of binary operations whose operands both yield series, but never match
+	TotalEmptyJoins int `json:"joins_empty_total,omitempty" yaml:"joins_empty_total,omitempty"`
+
+	// TotalSelectorsMissingLabels represents the total amount of probed selectors lacking labels of their grouping or matching clauses
+	TotalSelectorsMissingLabels int `json:"selectors_missing_labels_total,omitempty" yaml:"selectors_missing_labels_total,omitempty"`
+
+	// TotalSelectorsDeadAlternatives represents the total amount of probed selectors with regex alternatives without a result value
+	TotalSelectorsDeadAlternatives int `json:"selectors_dead_alternatives_total,omitempty" yaml:"selectors_dead_alternatives_total,omitempty"`
+
+	// TotalSelectorsWithWarnings represents the total amount of probed selectors Prometheus returned warnings for
+	TotalSelectorsWithWarnings int `json:"selectors_with_warnings_total,omitempty" yaml:"selectors_with_warnings_total,omitempty"`
+
+	// TotalSelectorsPartialResponse represents the total amount of probed selectors probed from a partial response
+	TotalSelectorsPartialResponse int `json:"selectors_partial_response_total,omitempty" yaml:"selectors_partial_response_total,omitempty"`
+
+	// TotalProbeRetries represents the total amount of queries retried after a transient error
+	TotalProbeRetries int `json:"probe_retries_total,omitempty" yaml:"probe_retries_total,omitempty"`
+
+	// TotalSelectorsBaselined represents the total amount of probed selectors without a result value recorded in the baseline.
+	// These are included in TotalSelectorsFailed.
+	TotalSelectorsBaselined int `json:"selectors_baselined_total,omitempty" yaml:"selectors_baselined_total,omitempty"`
+
+	// BaselineFixed represents the baseline entries whose selectors returned a result value, which can be removed from the baseline
+	BaselineFixed []BaselineEntry `json:"baseline_fixed,omitempty" yaml:"baseline_fixed,omitempty"`
+}
+
+// BaselineEntry represents a selector without a result value recorded in a baseline.
+type BaselineEntry struct {
+	// File represents the file name of the rule
+	File string `json:"file" yaml:"file"`
+
+	// Group represents the group name of the rule
+	Group string `json:"group" yaml:"group"`
+
+	// Rule represents the recording rule or alert name
+	Rule string `json:"rule" yaml:"rule"`
+
+	// Selector represents the rule's PromQL selector
+	Selector string `json:"selector" yaml:"selector"`
 }
 
 // Sections represents a collection of sections.
@@ -172,11 +266,333 @@ type Section struct {
 	// Expression represents the rule's PromQL expression string
 	Expression string `json:"expression" yaml:"expression"`
 
+	// Type represents the rule type, see RuleType* constants
+	Type string `json:"type,omitempty" yaml:"type,omitempty"`
+
+	// IntervalSeconds represents the evaluation interval of the rule's group, zero if unknown
+	IntervalSeconds float64 `json:"interval_seconds,omitempty" yaml:"interval_seconds,omitempty"`
+
+	// ForSeconds represents the duration an alerting rule's condition must hold before it fires
+	ForSeconds float64 `json:"for_seconds,omitempty" yaml:"for_seconds,omitempty"`
+
+	// KeepFiringForSeconds represents the duration an alerting rule keeps firing after its condition cleared
+	KeepFiringForSeconds float64 `json:"keep_firing_for_seconds,omitempty" yaml:"keep_firing_for_seconds,omitempty"`
+
+	// Labels represents the labels the rule adds to its results
+	Labels map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+
+	// Annotations represents the annotations of an alerting rule
+	Annotations map[string]string `json:"annotations,omitempty" yaml:"annotations,omitempty"`
+
 	// NoResults represents a list of the rule's PromQL selectors which did not successfully returned a result value
 	NoResults []string `json:"no_results" yaml:"no_results"`
 
 	// Results represents a list of the rule's PromQL selectors which successfully returned a result value
 	Results []string `json:"results" yaml:"results"`
+
+	// ExpectedEmpty represents a list of the rule's PromQL selectors which did not return a result value, but are expected to
+	// at times, like the selectors within absent() or on the right-hand side of unless
+	ExpectedEmpty []string `json:"expected_empty,omitempty" yaml:"expected_empty,omitempty"`
+
+	// Suppressed represents a list of the rule's PromQL selectors which did not return a result value, but whose findings are suppressed by the rule
+	Suppressed []SuppressedSelector `json:"suppressed,omitempty" yaml:"suppressed,omitempty"`
+
+	// Baselined represents the selectors of NoResults recorded in the baseline
+	Baselined []string `json:"baselined,omitempty" yaml:"baselined,omitempty"`
+
+	// Selectors represents additional findings for individual selectors of the rule
+	Selectors []SelectorDetail `json:"selectors,omitempty" yaml:"selectors,omitempty"`
+
+	// EmptyJoins represents the rule's binary operations whose operands both yield series, but never match
+	EmptyJoins []EmptyJoin `json:"empty_joins,omitempty" yaml:"empty_joins,omitempty"`
+
+	// Status represents the outcome of evaluating the rule's whole expression, see RuleStatus* constants, empty if not evaluated
+	Status string `json:"status,omitempty" yaml:"status,omitempty"`
+
+	// Series represents the number of series the rule's whole expression yielded when evaluated
+	Series int `json:"series,omitempty" yaml:"series,omitempty"`
+
+	// Backtest represents how the alerting rule would have behaved over historical data, nil if not backtested
+	Backtest *Backtest `json:"backtest,omitempty" yaml:"backtest,omitempty"`
+
+	// Error represents the query or parse error the rule could not be checked because of
+	Error string `json:"error,omitempty" yaml:"error,omitempty"`
+
+	// Unchecked reports whether the rule could not be checked before the run's deadline
+	Unchecked bool `json:"unchecked,omitempty" yaml:"unchecked,omitempty"`
+}
+
+// SelectorDetail represents additional findings for a single selector.
+type SelectorDetail struct {
+	// Selector represents the PromQL selector the findings belong to
+	Selector string `json:"selector" yaml:"selector"`
+
+	// Presence represents whether the selector returned a result value at the
+	// evaluation timestamp or within the lookback window only, see Presence* constants
+	Presence string `json:"presence,omitempty" yaml:"presence,omitempty"`
+
+	// LastSeen represents the timestamp of the selector's newest sample, nil if unknown
+	LastSeen *time.Time `json:"last_seen,omitempty" yaml:"last_seen,omitempty"`
+
+	// Stale reports whether the selector's newest sample is older than the
+	// configured max age, or there is none within NotSeenWithinSeconds
+	Stale bool `json:"stale,omitempty" yaml:"stale,omitempty"`
+
+	// NotSeenWithinSeconds represents the window searched for the selector's
+	// newest sample without finding any, zero if one was found or none was searched
+	NotSeenWithinSeconds float64 `json:"not_seen_within_seconds,omitempty" yaml:"not_seen_within_seconds,omitempty"`
+
+	// Series represents the number of series the selector yielded when probed
+	Series int `json:"series,omitempty" yaml:"series,omitempty"`
+
+	// SeriesLimit represents the cardinality threshold the selector exceeds, zero if within its threshold
+	SeriesLimit int `json:"series_limit,omitempty" yaml:"series_limit,omitempty"`
+
+	// NotRecorded reports whether the selector refers to a recording rule of the run which didn't record any series yet, while the recording rule's source selectors all return a result value
+	NotRecorded bool `json:"not_recorded,omitempty" yaml:"not_recorded,omitempty"`
+
+	// DependencyChains represents the paths from the selector through the recording rules of the run it depends on down to their source selectors without a result value, e.g. [job:foo:rate5m foo_total]
+	DependencyChains [][]string `json:"dependency_chains,omitempty" yaml:"dependency_chains,omitempty"`
+
+	// Diagnosis explains why the selector did not return a result value
+	Diagnosis *Diagnosis `json:"diagnosis,omitempty" yaml:"diagnosis,omitempty"`
+
+	// MissingLabels represents the labels of the grouping and matching clauses applying to the selector which not all of its series carry
+	MissingLabels []MissingLabel `json:"missing_labels,omitempty" yaml:"missing_labels,omitempty"`
+
+	// DeadAlternatives represents the alternatives of the selector's regex matchers which don't return a result value on their own
+	DeadAlternatives []string `json:"dead_alternatives,omitempty" yaml:"dead_alternatives,omitempty"`
+
+	// Warnings represents the warnings Prometheus returned along with the selector's probe results
+	Warnings []string `json:"warnings,omitempty" yaml:"warnings,omitempty"`
+
+	// PartialResponse reports whether any of the warnings tells the probe result may be incomplete
+	PartialResponse bool `json:"partial_response,omitempty" yaml:"partial_response,omitempty"`
+}
+
+// SuppressedSelector represents a selector without a result value whose finding is suppressed by its rule.
+type SuppressedSelector struct {
+	// Selector represents the suppressed PromQL selector
+	Selector string `json:"selector" yaml:"selector"`
+
+	// Expires represents the time the suppression stops applying at, nil if it never expires
+	Expires *time.Time `json:"expires,omitempty" yaml:"expires,omitempty"`
+
+	// Reason represents why the finding is suppressed
+	Reason string `json:"reason,omitempty" yaml:"reason,omitempty"`
+}
+
+// MissingLabel represents a grouping or matching label which series of a selector lack.
+type MissingLabel struct {
+	// Label represents the label name of a by, on or group_left/group_right clause
+	Label string `json:"label" yaml:"label"`
+
+	// Series represents the number of the selector's series without the label
+	Series int `json:"series" yaml:"series"`
+
+	// Total represents the number of the selector's series checked for the label, over the lookback window if configured
+	Total int `json:"total" yaml:"total"`
+}
+
+// EmptyJoin represents a binary operation with vector matching whose operands both yield series, but never match.
+type EmptyJoin struct {
+	// Expression represents the binary operation
+	Expression string `json:"expression" yaml:"expression"`
+
+	// Matching represents the on or ignoring clause of the operation, empty if all labels but the metric name are matched
+	Matching string `json:"matching,omitempty" yaml:"matching,omitempty"`
+
+	// LHS represents the number of distinct match keys of the left-hand side
+	LHS int `json:"lhs" yaml:"lhs"`
+
+	// RHS represents the number of distinct match keys of the right-hand side
+	RHS int `json:"rhs" yaml:"rhs"`
+
+	// LHSExample represents a match key of the left-hand side
+	LHSExample string `json:"lhs_example" yaml:"lhs_example"`
+
+	// RHSExample represents a match key of the right-hand side
+	RHSExample string `json:"rhs_example" yaml:"rhs_example"`
+}
+
+// Possible values of SelectorDetail.Presence.
+const (
+	// PresenceNow means the selector returned a result value at the evaluation timestamp.
+	PresenceNow = "present"
+
+	// PresenceWindow means the selector returned a result value within the lookback window only.
+	PresenceWindow = "window"
+
+	// PresenceAbsent means the selector returned no result value within the lookback window.
+	PresenceAbsent = "absent"
+)
+
+// Backtest represents how an alerting rule would have behaved over a range of historical data.
+type Backtest struct {
+	// RangeSeconds represents the backtested window ending at the evaluation timestamp
+	RangeSeconds float64 `json:"range_seconds" yaml:"range_seconds"`
+
+	// StepSeconds represents the resolution the rule's expression was evaluated at
+	StepSeconds float64 `json:"step_seconds" yaml:"step_seconds"`
+
+	// Pending represents the number of times an alert would have become pending
+	Pending int `json:"pending" yaml:"pending"`
+
+	// Firing represents the number of times an alert would have fired
+	Firing int `json:"firing" yaml:"firing"`
+
+	// PendingSeconds represents how long alerts would have been pending with none firing
+	PendingSeconds float64 `json:"pending_seconds" yaml:"pending_seconds"`
+
+	// FiringSeconds represents how long at least one alert would have been firing
+	FiringSeconds float64 `json:"firing_seconds" yaml:"firing_seconds"`
+
+	// FiringRatio represents the share of the range at least one alert would have been firing for
+	FiringRatio float64 `json:"firing_ratio" yaml:"firing_ratio"`
+
+	// NeverFired reports whether no alert would have fired within the range
+	NeverFired bool `json:"never_fired" yaml:"never_fired"`
+
+	// Continuous reports whether alerts would have fired nearly continuously within the range
+	Continuous bool `json:"continuous" yaml:"continuous"`
+}
+
+// Possible values of Section.Type.
+const (
+	// RuleTypeAlert means the section's rule is an alerting rule.
+	RuleTypeAlert = "alert"
+
+	// RuleTypeRecord means the section's rule is a recording rule or an inline query.
+	RuleTypeRecord = "record"
+)
+
+// RuleMetadata represents the metadata of a section's rule.
+type RuleMetadata struct {
+	// Type represents the rule type, see RuleType* constants
+	Type string
+
+	// Interval represents the evaluation interval of the rule's group, zero if unknown
+	Interval time.Duration
+
+	// For represents the duration an alerting rule's condition must hold before it fires
+	For time.Duration
+
+	// KeepFiringFor represents the duration an alerting rule keeps firing after its condition cleared
+	KeepFiringFor time.Duration
+
+	// Labels represents the labels the rule adds to its results
+	Labels map[string]string
+
+	// Annotations represents the annotations of an alerting rule
+	Annotations map[string]string
+}
+
+// Possible values of Section.Status.
+const (
+	// RuleStatusResults means the rule's expression yields series, for an alerting rule its condition is currently true.
+	RuleStatusResults = "results"
+
+	// RuleStatusEmpty means the rule's expression yields no series although all of its selectors return a result value.
+	RuleStatusEmpty = "empty"
+
+	// RuleStatusBroken means the rule's expression yields no series because selectors return no result value or joins never match.
+	RuleStatusBroken = "broken"
+)
+
+// Diagnosis represents the outcome of a matcher-level drill-down for a selector without results.
+type Diagnosis struct {
+	// MetricMissing reports whether the bare metric name has no series at all
+	MetricMissing bool `json:"metric_missing" yaml:"metric_missing"`
+
+	// Culprits represents the label matchers which, once removed on their own, make the selector return a result value
+	Culprits []string `json:"culprits,omitempty" yaml:"culprits,omitempty"`
+
+	// Suggestions represents existing metric names or label matchers closest to the missing ones
+	Suggestions []string `json:"suggestions,omitempty" yaml:"suggestions,omitempty"`
+}
+
+// SectionOption represents optional section data.
+type SectionOption func(*Section)
+
+// WithRuleMetadata attaches the metadata of the section's rule.
+func WithRuleMetadata(metadata RuleMetadata) SectionOption {
+	return func(s *Section) {
+		s.Type = metadata.Type
+		s.IntervalSeconds = metadata.Interval.Seconds()
+		s.ForSeconds = metadata.For.Seconds()
+		s.KeepFiringForSeconds = metadata.KeepFiringFor.Seconds()
+		s.Labels = metadata.Labels
+		s.Annotations = metadata.Annotations
+	}
+}
+
+// WithExpectedEmpty attaches the selectors without a result value the section's rule expects to be empty at times.
+func WithExpectedEmpty(selectors ...string) SectionOption {
+	return func(s *Section) {
+		s.ExpectedEmpty = append(s.ExpectedEmpty, selectors...)
+	}
+}
+
+// WithSuppressed attaches the selectors without a result value whose findings are suppressed by the section's rule.
+func WithSuppressed(selectors ...SuppressedSelector) SectionOption {
+	return func(s *Section) {
+		s.Suppressed = append(s.Suppressed, selectors...)
+	}
+}
+
+// WithBaselined marks selectors without a result value of the section as recorded in the baseline.
+func WithBaselined(selectors ...string) SectionOption {
+	return func(s *Section) {
+		s.Baselined = append(s.Baselined, selectors...)
+	}
+}
+
+// WithSelectorDetails attaches additional per-selector findings to a section.
+func WithSelectorDetails(details ...SelectorDetail) SectionOption {
+	return func(s *Section) {
+		s.Selectors = append(s.Selectors, details...)
+	}
+}
+
+// WithEmptyJoins attaches the rule's binary operations which never match to a section.
+func WithEmptyJoins(joins ...EmptyJoin) SectionOption {
+	return func(s *Section) {
+		s.EmptyJoins = append(s.EmptyJoins, joins...)
+	}
+}
+
+// WithStatus attaches the outcome of evaluating the rule's whole expression and its series count to a section.
+func WithStatus(status string, series int) SectionOption {
+	return func(s *Section) {
+		s.Status = status
+		s.Series = series
+	}
+}
+
+// WithBacktest attaches how the section's alerting rule would have behaved over historical data.
+func WithBacktest(backtest Backtest) SectionOption {
+	return func(s *Section) {
+		s.Backtest = &backtest
+	}
+}
+
+// WithError marks a section's rule as not checked because of the given error.
+func WithError(err string) SectionOption {
+	return func(s *Section) {
+		s.Error = err
+	}
+}
+
+// WithUnchecked marks a section's rule as not checked before the run's deadline.
+func WithUnchecked() SectionOption {
+	return func(s *Section) {
+		s.Unchecked = true
+	}
+}
+
+// failing reports whether the section has a selector without a result, a rule error or was not checked.
+func (s Section) failing() bool {
+	return len(s.NoResults) > 0 || s.Error != "" || s.Unchecked
 }
 
 // Len returns the list size.
@@ -222,19 +638,79 @@ func (b *Builder) clear() {
 }
 
 // AddSection adds a new section to the report.
-func (b *Builder) AddSection(file, group, name, expression string, failed, success []string) {
-	b.Report.Sections = append(b.Report.Sections, Section{
+func (b *Builder) AddSection(file, group, name, expression string, failed, success []string, opts ...SectionOption) {
+	section := Section{
 		File:       file,
 		Group:      group,
 		Name:       name,
 		Expression: expression,
 		NoResults:  failed,
 		Results:    success,
-	})
+	}
+	for _, opt := range opts {
+		opt(&section)
+	}
+	b.Report.Sections = append(b.Report.Sections, section)
 
 	b.Report.TotalRules++
 	b.Report.TotalSelectorsFailed += len(failed)
 	b.Report.TotalSelectorsSuccess += len(success)
+	b.Report.TotalSelectorsExpectedEmpty += len(section.ExpectedEmpty)
+	b.Report.TotalSelectorsSuppressed += len(section.Suppressed)
+	b.Report.TotalSelectorsBaselined += len(section.Baselined)
+	if section.Error != "" {
+		b.Report.TotalRulesErrored++
+	}
+	if section.Unchecked {
+		b.Report.TotalRulesUnchecked++
+	}
+	b.Report.TotalEmptyJoins += len(section.EmptyJoins)
+	if bt := section.Backtest; bt != nil {
+		b.Report.TotalAlertsBacktested++
+		if bt.NeverFired {
+			b.Report.TotalAlertsNeverFired++
+		}
+		if bt.Continuous {
+			b.Report.TotalAlertsFiringContinuously++
+		}
+	}
+	switch section.Status {
+	case RuleStatusResults:
+		b.Report.TotalRulesWithResults++
+	case RuleStatusEmpty:
+		b.Report.TotalRulesEmpty++
+	case RuleStatusBroken:
+		b.Report.TotalRulesBroken++
+	}
+	for _, d := range section.Selectors {
+		if d.Presence == PresenceWindow {
+			b.Report.TotalSelectorsWindowOnly++
+		}
+		if d.Stale {
+			b.Report.TotalSelectorsStale++
+		}
+		if d.NotRecorded {
+			b.Report.TotalSelectorsNotRecorded++
+		}
+		if len(d.DependencyChains) > 0 {
+			b.Report.TotalSelectorsWithDependencyChains++
+		}
+		if d.SeriesLimit > 0 {
+			b.Report.TotalSelectorsOverSeriesLimit++
+		}
+		if len(d.MissingLabels) > 0 {
+			b.Report.TotalSelectorsMissingLabels++
+		}
+		if len(d.DeadAlternatives) > 0 {
+			b.Report.TotalSelectorsDeadAlternatives++
+		}
+		if len(d.Warnings) > 0 {
+			b.Report.TotalSelectorsWithWarnings++
+		}
+		if d.PartialResponse {
+			b.Report.TotalSelectorsPartialResponse++
+		}
+	}
 }
 
 // AddTotalCheckedGroups adds checked groups to the total amount.
@@ -243,6 +719,16 @@ func (b *Builder) AddTotalCheckedGroups(count int) {
 	b.Report.TotalGroups += count
 }
 
+// AddProbeRetries adds retried queries to the total amount.
+func (b *Builder) AddProbeRetries(count int) {
+	b.Report.TotalProbeRetries += count
+}
+
+// AddBaselineFixed adds baseline entries whose selectors returned a result value.
+func (b *Builder) AddBaselineFixed(entries ...BaselineEntry) {
+	b.Report.BaselineFixed = append(b.Report.BaselineFixed, entries...)
+}
+
 // reportEnvelope mirrors the shape Builder marshals to json/yaml (a single
 // "promcheck" key), decoupled from Builder itself so rendering can swap in a
 // filtered Report (see renderedReport) without mutating the Builder's state.
@@ -251,7 +737,7 @@ type reportEnvelope struct {
 }
 
 // renderedReport returns the Report to marshal/print. When onlyFailing is
-// set, sections without a failing selector are dropped from the copy, but
+// set, sections without a failing selector, rule error or unchecked rule are dropped from the copy, but
 // the summary totals (which are accumulated independently in AddSection)
 // still reflect every section from the full run.
 func (b *Builder) renderedReport() Report {
@@ -261,7 +747,7 @@ func (b *Builder) renderedReport() Report {
 	}
 	filtered := make(Sections, 0, len(r.Sections))
 	for _, s := range r.Sections {
-		if len(s.NoResults) > 0 {
+		if s.failing() {
 			filtered = append(filtered, s)
 		}
 	}
@@ -7,6 +7,7 @@ import (
 	"math"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 )
@@ -319,3 +320,534 @@ func TestFinalize_ZeroSelectorsNoNaN(t *testing.T) {
 	require.False(t, math.IsNaN(float64(b.Report.RatioFailedTotal)))
 	require.Equal(t, float32(0), b.Report.RatioFailedTotal)
 }
+
+func TestBuilder_RendersDiagnoses(t *testing.T) {
+	b := NewBuilder(WithWriter(io.Discard), WithoutColor())
+	b.AddSection(
+		"f.yaml", "g", "KubePodNotReady", `kube_pod_info{job="kube-state-metrics"}`,
+		[]string{`kube_pod_info{job="kube-state-metrics"}`, `node_cpu{mode="idle"}`},
+		nil,
+		WithSelectorDetails(
+			SelectorDetail{
+				Selector:  `kube_pod_info{job="kube-state-metrics"}`,
+				Diagnosis: &Diagnosis{Culprits: []string{`job="kube-state-metrics"`}},
+			},
+			SelectorDetail{
+				Selector:  `node_cpu{mode="idle"}`,
+				Diagnosis: &Diagnosis{MetricMissing: true, Suggestions: []string{"node_cpu_seconds_total"}},
+			},
+		),
+	)
+
+	tree, err := b.ToTree()
+	require.NoError(t, err)
+	require.Contains(t, tree, `
+            ├── [✖] kube_pod_info{job="kube-state-metrics"}
+            │   └── culprit: job="kube-state-metrics"
+            └── [✖] node_cpu{mode="idle"}
+                ├── metric has no series at all
+                └── did you mean: node_cpu_seconds_total`)
+
+	raw, err := b.ToJSON()
+	require.NoError(t, err)
+	require.Contains(t, raw, `"culprits": [
+                "job=\"kube-state-metrics\""
+              ]`)
+	require.Contains(t, raw, `"metric_missing": true`)
+	require.Contains(t, raw, `"suggestions": [
+                "node_cpu_seconds_total"
+              ]`)
+
+	yamlRaw, err := b.ToYAML()
+	require.NoError(t, err)
+	require.Contains(t, yamlRaw, "- node_cpu_seconds_total")
+}
+
+func TestBuilder_RendersLookbackPresence(t *testing.T) {
+	b := NewBuilder(WithWriter(io.Discard), WithoutColor())
+	b.AddSection(
+		"f.yaml", "g", "BatchJobFailed", `batch_job_failed or up or gone`,
+		[]string{`gone`},
+		[]string{`up`, `batch_job_failed`},
+		WithSelectorDetails(
+			SelectorDetail{Selector: `up`, Presence: PresenceNow},
+			SelectorDetail{Selector: `batch_job_failed`, Presence: PresenceWindow},
+			SelectorDetail{Selector: `gone`, Presence: PresenceAbsent},
+		),
+	)
+
+	tree, err := b.ToTree()
+	require.NoError(t, err)
+	require.Contains(t, tree, "[✔] up\n")
+	require.Contains(t, tree, "[✔] batch_job_failed (within lookback window only)")
+	require.Contains(t, tree, "[✖] gone (no result within lookback window either)")
+	require.Contains(t, tree, "Results found within lookback window only: 1")
+
+	raw, err := b.ToJSON()
+	require.NoError(t, err)
+	require.Contains(t, raw, `"presence": "window"`)
+	require.Contains(t, raw, `"presence": "absent"`)
+	require.Contains(t, raw, `"selectors_window_only_total": 1`)
+	require.Contains(t, raw, `"selectors_success_total": 2`)
+}
+
+func TestBuilder_RendersLastSeen(t *testing.T) {
+	fresh := time.Date(2026, 10, 16, 11, 59, 30, 0, time.UTC)
+	old := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+	b := NewBuilder(WithWriter(io.Discard), WithoutColor())
+	b.AddSection(
+		"f.yaml", "g", "BatchJobFailed", `batch_job_failed or up`,
+		[]string{`batch_job_failed`},
+		[]string{`up`},
+		WithSelectorDetails(
+			SelectorDetail{Selector: `up`, LastSeen: &fresh},
+			SelectorDetail{Selector: `batch_job_failed`, LastSeen: &old, Stale: true},
+		),
+	)
+	b.AddSection(
+		"f.yaml", "g", "CronJobFailed", `cron_job_failed`,
+		[]string{`cron_job_failed`},
+		nil,
+		WithSelectorDetails(SelectorDetail{Selector: `cron_job_failed`, Stale: true, NotSeenWithinSeconds: 7200}),
+	)
+
+	tree, err := b.ToTree()
+	require.NoError(t, err)
+	require.Contains(t, tree, "[✔] up\n")
+	require.Contains(t, tree, `
+        │   └── [✖] batch_job_failed
+        │       └── stale, last seen: 2026-10-16T09:00:00Z`)
+	require.Contains(t, tree, `
+            └── [✖] cron_job_failed
+                └── stale, not seen within: 2h0m0s`)
+	require.Contains(t, tree, "Stale selectors (newest sample older than max age, or none): 2")
+
+	raw, err := b.ToJSON()
+	require.NoError(t, err)
+	require.Contains(t, raw, `"last_seen": "2026-10-16T09:00:00Z"`)
+	require.Contains(t, raw, `"not_seen_within_seconds": 7200`)
+	require.Contains(t, raw, `"stale": true`)
+	require.Contains(t, raw, `"selectors_stale_total": 2`)
+}
+
+func TestBuilder_RendersSeriesCounts(t *testing.T) {
+	b := NewBuilder(WithWriter(io.Discard), WithoutColor())
+	b.AddSection(
+		"f.yaml", "g", "KubePodInfo", `kube_pod_info or up`,
+		nil,
+		[]string{`kube_pod_info`, `up`},
+		WithSelectorDetails(
+			SelectorDetail{Selector: `kube_pod_info`, Series: 1423, SeriesLimit: 1000},
+			SelectorDetail{Selector: `up`, Series: 12},
+		),
+	)
+
+	tree, err := b.ToTree()
+	require.NoError(t, err)
+	require.Contains(t, tree, `
+            ├── [✔ 1423] kube_pod_info
+            │   └── warning: 1423 series exceed the limit of 1000
+            └── [✔ 12] up`)
+	require.Contains(t, tree, "Selectors exceeding their series limit: 1")
+
+	raw, err := b.ToJSON()
+	require.NoError(t, err)
+	require.Contains(t, raw, `"series": 1423`)
+	require.Contains(t, raw, `"series_limit": 1000`)
+	require.Contains(t, raw, `"selectors_over_series_limit_total": 1`)
+
+	yamlRaw, err := b.ToYAML()
+	require.NoError(t, err)
+	require.Contains(t, yamlRaw, "series: 12")
+}
+
+func TestBuilder_RendersRuleErrors(t *testing.T) {
+	b := NewBuilder(WithWriter(io.Discard), WithoutColor(), WithOnlyFailing())
+	b.AddSection("f.yaml", "g", "Broken", `sum(up`, nil, nil, WithError(`parse error: unclosed left parenthesis`))
+	b.AddSection("f.yaml", "g", "Fine", `up`, nil, []string{`up`})
+
+	tree, err := b.ToTree()
+	require.NoError(t, err)
+	require.Contains(t, tree, `
+        └── [0/0] Broken
+            └── error: parse error: unclosed left parenthesis`)
+	require.NotContains(t, tree, "Fine", "only-failing must drop sections without errors or failed selectors")
+	require.Contains(t, tree, "Rules not checked because of an error: 1")
+
+	raw, err := b.ToJSON()
+	require.NoError(t, err)
+	require.Contains(t, raw, `"error": "parse error: unclosed left parenthesis"`)
+	require.Contains(t, raw, `"rules_errored_total": 1`)
+
+	yamlRaw, err := b.ToYAML()
+	require.NoError(t, err)
+	require.Contains(t, yamlRaw, "rules_errored_total: 1")
+}
+
+func TestBuilder_RendersProbeRetries(t *testing.T) {
+	b := NewBuilder(WithWriter(io.Discard), WithoutColor())
+	b.AddSection("f.yaml", "g", "Fine", `up`, nil, []string{`up`})
+	b.AddProbeRetries(2)
+	b.AddProbeRetries(1)
+
+	tree, err := b.ToTree()
+	require.NoError(t, err)
+	require.Contains(t, tree, "Queries retried after a transient error: 3")
+
+	raw, err := b.ToJSON()
+	require.NoError(t, err)
+	require.Contains(t, raw, `"probe_retries_total": 3`)
+}
+
+func TestBuilder_RendersUncheckedRules(t *testing.T) {
+	b := NewBuilder(WithWriter(io.Discard), WithoutColor(), WithOnlyFailing())
+	b.AddSection("f.yaml", "g", "Slow", `up`, nil, nil, WithUnchecked())
+	b.AddSection("f.yaml", "g", "Fine", `up`, nil, []string{`up`})
+
+	tree, err := b.ToTree()
+	require.NoError(t, err)
+	require.Contains(t, tree, `
+        └── [0/0] Slow
+            └── not checked: run deadline exceeded`)
+	require.NotContains(t, tree, "Fine")
+	require.Contains(t, tree, "Rules not checked before the run's deadline: 1")
+
+	raw, err := b.ToJSON()
+	require.NoError(t, err)
+	require.Contains(t, raw, `"unchecked": true`)
+	require.Contains(t, raw, `"rules_unchecked_total": 1`)
+}
+
+func TestBuilder_RendersQueryWarnings(t *testing.T) {
+	annotation := `PromQL info: metric might not be a counter, name does not end in _total/_sum/_count/_bucket: "http_requests"`
+	partial := `receive series from Addr: store-1:10901: rpc error: code = Unavailable`
+	b := NewBuilder(WithWriter(io.Discard), WithoutColor())
+	b.AddSection(
+		"f.yaml", "g", "ErrorRate", `rate(http_requests[5m]) / up`,
+		nil,
+		[]string{`http_requests[5m]`, `up`},
+		WithSelectorDetails(
+			SelectorDetail{Selector: `http_requests[5m]`, Warnings: []string{annotation}},
+			SelectorDetail{Selector: `up`, Warnings: []string{partial}, PartialResponse: true},
+		),
+	)
+
+	tree, err := b.ToTree()
+	require.NoError(t, err)
+	require.Contains(t, tree, `
+            ├── [✔] http_requests[5m]
+            │   └── query warning: `+annotation+`
+            └── [✔] up
+                └── query warning: `+partial)
+	require.Contains(t, tree, "Selectors with query warnings: 2")
+	require.Contains(t, tree, "Selectors probed from a partial response: 1")
+
+	raw, err := b.ToJSON()
+	require.NoError(t, err)
+	require.Contains(t, raw, `"partial_response": true`)
+	require.Contains(t, raw, `"selectors_with_warnings_total": 2`)
+	require.Contains(t, raw, `"selectors_partial_response_total": 1`)
+
+	yamlRaw, err := b.ToYAML()
+	require.NoError(t, err)
+	require.Contains(t, yamlRaw, "warnings:\n")
+	require.Contains(t, yamlRaw, "selectors_partial_response_total: 1")
+}
+
+func TestBuilder_RendersDeadAlternatives(t *testing.T) {
+	b := NewBuilder(WithWriter(io.Discard), WithoutColor())
+	b.AddSection(
+		"f.yaml", "g", "JobDown", `up{job=~"api|worker|cron"} == 0`,
+		nil,
+		[]string{`up{job=~"api|worker|cron"}`},
+		WithSelectorDetails(SelectorDetail{Selector: `up{job=~"api|worker|cron"}`, DeadAlternatives: []string{`job="worker"`, `job="cron"`}}),
+	)
+
+	tree, err := b.ToTree()
+	require.NoError(t, err)
+	require.Contains(t, tree, `
+            └── [✔] up{job=~"api|worker|cron"}
+                ├── dead alternative: job="worker"
+                └── dead alternative: job="cron"`)
+	require.Contains(t, tree, "Selectors with dead regex alternatives: 1")
+	require.Contains(t, tree, "No Results found 0", "dead alternatives must not fail the selector")
+
+	raw, err := b.ToJSON()
+	require.NoError(t, err)
+	require.Contains(t, raw, `"dead_alternatives": [`)
+	require.Contains(t, raw, `"selectors_dead_alternatives_total": 1`)
+
+	yamlRaw, err := b.ToYAML()
+	require.NoError(t, err)
+	require.Contains(t, yamlRaw, "dead_alternatives:\n")
+}
+
+func TestBuilder_RendersMissingLabels(t *testing.T) {
+	b := NewBuilder(WithWriter(io.Discard), WithoutColor())
+	b.AddSection(
+		"f.yaml", "g", "TeamErrors", `sum by (team) (errors)`,
+		nil,
+		[]string{`errors`},
+		WithSelectorDetails(SelectorDetail{Selector: `errors`, Series: 5, MissingLabels: []MissingLabel{{Label: "team", Series: 3, Total: 5}}}),
+	)
+
+	tree, err := b.ToTree()
+	require.NoError(t, err)
+	require.Contains(t, tree, `
+            └── [✔ 5] errors
+                └── missing label: team (3 of 5 series lack it)`)
+	require.Contains(t, tree, "Selectors lacking labels of their grouping or matching clauses: 1")
+
+	raw, err := b.ToJSON()
+	require.NoError(t, err)
+	require.Contains(t, raw, `"label": "team"`)
+	require.Contains(t, raw, `"selectors_missing_labels_total": 1`)
+
+	yamlRaw, err := b.ToYAML()
+	require.NoError(t, err)
+	require.Contains(t, yamlRaw, "missing_labels:\n")
+}
+
+func TestBuilder_RendersMissingLabelsOfLookbackWindow(t *testing.T) {
+	b := NewBuilder(WithWriter(io.Discard), WithoutColor())
+	b.AddSection(
+		"f.yaml", "g", "TeamErrors", `sum by (team) (errors)`,
+		nil,
+		[]string{`errors`},
+		WithSelectorDetails(SelectorDetail{Selector: `errors`, Series: 3, MissingLabels: []MissingLabel{{Label: "team", Series: 5, Total: 7}}}),
+	)
+
+	tree, err := b.ToTree()
+	require.NoError(t, err)
+	require.Contains(t, tree, "missing label: team (5 of 7 series lack it)", "missing labels are counted against the series they were checked on")
+}
+
+func TestBuilder_RendersEmptyJoins(t *testing.T) {
+	b := NewBuilder(WithWriter(io.Discard), WithoutColor())
+	b.AddSection(
+		"f.yaml", "g", "PodMemory", `foo * on (pod) bar`,
+		nil,
+		[]string{`foo`, `bar`},
+		WithEmptyJoins(EmptyJoin{
+			Expression: `foo * on (pod) bar`, Matching: "on (pod)",
+			LHS: 2, RHS: 1, LHSExample: `{pod="a"}`, RHSExample: `{pod="A"}`,
+		}),
+	)
+
+	tree, err := b.ToTree()
+	require.NoError(t, err)
+	require.Contains(t, tree, `
+        └── [2/2] PodMemory
+            ├── empty join: foo * on (pod) bar
+            │   ├── left: 2 match keys, e.g. {pod="a"}
+            │   └── right: 1 match keys, e.g. {pod="A"}
+            ├── [✔] foo`)
+	require.Contains(t, tree, "Joins which never match: 1")
+
+	raw, err := b.ToJSON()
+	require.NoError(t, err)
+	require.Contains(t, raw, `"matching": "on (pod)"`)
+	require.Contains(t, raw, `"joins_empty_total": 1`)
+
+	yamlRaw, err := b.ToYAML()
+	require.NoError(t, err)
+	require.Contains(t, yamlRaw, "empty_joins:\n")
+}
+
+func TestBuilder_RendersExpectedEmptySelectors(t *testing.T) {
+	b := NewBuilder(WithWriter(io.Discard), WithoutColor())
+	b.AddSection(
+		"f.yaml", "g", "HeartbeatMissing", `absent(heartbeat) or up == 0`,
+		nil,
+		[]string{`up`},
+		WithExpectedEmpty(`heartbeat`),
+	)
+
+	tree, err := b.ToTree()
+	require.NoError(t, err)
+	require.Contains(t, tree, `
+        └── [1/1] HeartbeatMissing
+            ├── [✔] up
+            └── [○] heartbeat (expected to be empty at times)`)
+	require.Contains(t, tree, "Selectors without results expected to be empty at times: 1")
+	require.Equal(t, 1, b.Report.TotalSelectorsSuccess)
+	require.Zero(t, b.Report.TotalSelectorsFailed, "expected empty selectors must not count as failed")
+
+	raw, err := b.ToJSON()
+	require.NoError(t, err)
+	require.Contains(t, raw, `"selectors_expected_empty_total": 1`)
+
+	yamlRaw, err := b.ToYAML()
+	require.NoError(t, err)
+	require.Contains(t, yamlRaw, "expected_empty:\n")
+}
+
+func TestBuilder_RendersSuppressedSelectors(t *testing.T) {
+	expires := time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
+	b := NewBuilder(WithWriter(io.Discard), WithoutColor())
+	b.AddSection(
+		"f.yaml", "g", "LegacyErrors", `legacy_errors_total > 0 or batch_errors_total > 0`,
+		nil,
+		nil,
+		WithSuppressed(
+			SuppressedSelector{Selector: `legacy_errors_total`, Expires: &expires, Reason: "migration"},
+			SuppressedSelector{Selector: `batch_errors_total`},
+		),
+	)
+
+	tree, err := b.ToTree()
+	require.NoError(t, err)
+	require.Contains(t, tree, `
+        └── [0/0] LegacyErrors
+            ├── [-] legacy_errors_total (suppressed until 2026-12-31T00:00:00Z: migration)
+            └── [-] batch_errors_total (suppressed)`)
+	require.Contains(t, tree, "Selectors without results suppressed by their rule: 2")
+	require.Zero(t, b.Report.TotalSelectorsFailed, "suppressed selectors must not count as failed")
+
+	raw, err := b.ToJSON()
+	require.NoError(t, err)
+	require.Contains(t, raw, `"expires": "2026-12-31T00:00:00Z"`)
+	require.Contains(t, raw, `"selectors_suppressed_total": 2`)
+
+	yamlRaw, err := b.ToYAML()
+	require.NoError(t, err)
+	require.Contains(t, yamlRaw, "suppressed:\n")
+}
+
+func TestBuilder_RendersRecordingRuleDependencies(t *testing.T) {
+	b := NewBuilder(WithWriter(io.Discard), WithoutColor())
+	b.AddSection(
+		"f.yaml", "g", "FooErrorsHigh", `job:foo_errors:rate5m / job:foo:rate5m > 0.1`,
+		[]string{`job:foo_errors:rate5m`},
+		[]string{`job:foo:rate5m`},
+		WithSelectorDetails(
+			SelectorDetail{Selector: `job:foo:rate5m`, NotRecorded: true},
+			SelectorDetail{Selector: `job:foo_errors:rate5m`, DependencyChains: [][]string{{"job:foo_errors:rate5m", `foo_errors_total[5m]`}}},
+		),
+	)
+
+	tree, err := b.ToTree()
+	require.NoError(t, err)
+	require.Contains(t, tree, `
+        └── [1/2] FooErrorsHigh
+            ├── [✔] job:foo:rate5m (not recorded yet, the sources of its recording rule have results)
+            └── [✖] job:foo_errors:rate5m
+                └── chain: FooErrorsHigh → job:foo_errors:rate5m → foo_errors_total[5m]`)
+	require.Contains(t, tree, "Results found via the sources of recording rules not recorded yet: 1")
+	require.Contains(t, tree, "Selectors of recording rules whose sources lack results: 1")
+
+	raw, err := b.ToJSON()
+	require.NoError(t, err)
+	require.Contains(t, raw, `"selectors_not_recorded_total": 1`)
+	require.Contains(t, raw, `"dependency_chains": [`)
+}
+
+func TestBuilder_RendersBaseline(t *testing.T) {
+	b := NewBuilder(WithWriter(io.Discard), WithoutColor())
+	b.AddSection("f.yaml", "g", "r", `foo and bar`, []string{`foo`, `bar`}, nil, WithBaselined(`foo`))
+	b.AddBaselineFixed(BaselineEntry{File: "f.yaml", Group: "g", Rule: "old", Selector: `baz`})
+
+	tree, err := b.ToTree()
+	require.NoError(t, err)
+	require.Contains(t, tree, `
+        └── [0/2] r
+            ├── [✖] foo (known from baseline)
+            └── [✖] bar`)
+	require.Contains(t, tree, "Selectors without results known from the baseline: 1")
+	require.Contains(t, tree, "Baseline entries fixed, ready to be removed: 1\n  f.yaml > g > old: baz")
+	require.Equal(t, 2, b.Report.TotalSelectorsFailed, "baselined selectors still count as failed")
+
+	raw, err := b.ToJSON()
+	require.NoError(t, err)
+	require.Contains(t, raw, `"baselined": [`)
+	require.Contains(t, raw, `"selectors_baselined_total": 1`)
+	require.Contains(t, raw, `"baseline_fixed": [`)
+
+	yamlRaw, err := b.ToYAML()
+	require.NoError(t, err)
+	require.Contains(t, yamlRaw, "baseline_fixed:\n")
+}
+
+func TestBuilder_RendersRuleStatus(t *testing.T) {
+	b := NewBuilder(WithWriter(io.Discard), WithoutColor())
+	b.AddSection("f.yaml", "g", "InstanceDown", `up == 0`, nil, []string{`up`}, WithStatus(RuleStatusResults, 2))
+	b.AddSection("f.yaml", "g", "HighErrors", `errors > 5`, nil, []string{`errors`}, WithStatus(RuleStatusEmpty, 0))
+	b.AddSection("f.yaml", "g", "Typo", `erors > 5`, []string{`erors`}, nil, WithStatus(RuleStatusBroken, 0))
+	b.AddSection("f.yaml", "g", "NotEvaluated", `up`, nil, []string{`up`})
+
+	tree, err := b.ToTree()
+	require.NoError(t, err)
+	require.Contains(t, tree, `
+        ├── [1/1] InstanceDown
+        │   ├── expression: 2 series
+        │   └── [✔] up`)
+	require.Contains(t, tree, "expression: no series, all selectors return results")
+	require.Contains(t, tree, "expression: no series, broken by selectors without results or joins which never match")
+	require.Contains(t, tree, "Rules evaluated: 3, With results: 1, Without results: 1, Broken: 1")
+
+	raw, err := b.ToJSON()
+	require.NoError(t, err)
+	require.Contains(t, raw, `"status": "results"`)
+	require.Contains(t, raw, `"rules_broken_total": 1`)
+
+	yamlRaw, err := b.ToYAML()
+	require.NoError(t, err)
+	require.Contains(t, yamlRaw, "status: empty\n")
+}
+
+func TestBuilder_RendersBacktests(t *testing.T) {
+	b := NewBuilder(WithWriter(io.Discard), WithoutColor())
+	b.AddSection("f.yaml", "g", "InstanceDown", `up == 0`, nil, []string{`up`}, WithBacktest(Backtest{
+		RangeSeconds: 86400, StepSeconds: 60, Pending: 4, Firing: 2,
+		PendingSeconds: 1200, FiringSeconds: 3600, FiringRatio: 3600.0 / 86400,
+	}))
+	b.AddSection("f.yaml", "g", "Watchdog", `vector(1)`, nil, nil, WithBacktest(Backtest{
+		RangeSeconds: 86400, StepSeconds: 60, Firing: 1, FiringSeconds: 86400, FiringRatio: 1, Continuous: true,
+	}))
+
+	tree, err := b.ToTree()
+	require.NoError(t, err)
+	require.Contains(t, tree, `
+        ├── [1/1] InstanceDown
+        │   ├── backtest over 24h0m0s: pending 4 times for 20m0s, firing 2 times for 1h0m0s (4.17%)
+        │   └── [✔] up`)
+	require.Contains(t, tree, `
+            └── backtest over 24h0m0s: pending 0 times for 0s, firing 1 times for 24h0m0s (100.00%)
+                └── warning: would have fired nearly continuously`)
+	require.Contains(t, tree, "Alerts backtested: 2, Never fired: 0, Firing nearly continuously: 1")
+
+	raw, err := b.ToJSON()
+	require.NoError(t, err)
+	require.Contains(t, raw, `"firing_seconds": 3600`)
+	require.Contains(t, raw, `"alerts_firing_continuously_total": 1`)
+
+	yamlRaw, err := b.ToYAML()
+	require.NoError(t, err)
+	require.Contains(t, yamlRaw, "backtest:\n")
+}
+
+func TestBuilder_RendersRuleMetadata(t *testing.T) {
+	b := NewBuilder(WithWriter(io.Discard), WithoutColor())
+	b.AddSection("f.yaml", "g", "InstanceDown", `up == 0`, nil, []string{`up`}, WithRuleMetadata(RuleMetadata{
+		Type:          RuleTypeAlert,
+		Interval:      30 * time.Second,
+		For:           5 * time.Minute,
+		KeepFiringFor: time.Minute,
+		Labels:        map[string]string{"severity": "critical"},
+		Annotations:   map[string]string{"summary": "instance down"},
+	}))
+
+	raw, err := b.ToJSON()
+	require.NoError(t, err)
+	require.Contains(t, raw, `"type": "alert"`)
+	require.Contains(t, raw, `"interval_seconds": 30`)
+	require.Contains(t, raw, `"for_seconds": 300`)
+	require.Contains(t, raw, `"keep_firing_for_seconds": 60`)
+	require.Contains(t, raw, `"severity": "critical"`)
+	require.Contains(t, raw, `"summary": "instance down"`)
+
+	yamlRaw, err := b.ToYAML()
+	require.NoError(t, err)
+	require.Contains(t, yamlRaw, "type: alert\n")
+	require.Contains(t, yamlRaw, "labels:\n")
+}
@@ -0,0 +1,307 @@
+package report
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+// MarkdownFormat renders a Diff as Markdown. It is only supported by Diff.
+const MarkdownFormat = "markdown"
+
+// ReadJSON reads a report in the json format of Builder.ToJSON.
+func ReadJSON(r io.Reader) (Report, error) {
+	var envelope reportEnvelope
+	if err := json.NewDecoder(r).Decode(&envelope); err != nil {
+		return Report{}, err
+	}
+	return envelope.Report, nil
+}
+
+// Diff represents the changes between the reports of two runs.
+type Diff struct {
+	// NewlyFailing represents the selectors without a result value which returned one, or didn't exist, in the old report
+	NewlyFailing []DiffSelector `json:"newly_failing" yaml:"newly_failing"`
+
+	// Recovered represents the selectors without a result value in the old report which no longer lack one
+	Recovered []DiffSelector `json:"recovered" yaml:"recovered"`
+
+	// AddedRules represents the rules of the new report which the old report lacks
+	AddedRules []DiffRule `json:"added_rules" yaml:"added_rules"`
+
+	// RemovedRules represents the rules of the old report which the new report lacks
+	RemovedRules []DiffRule `json:"removed_rules" yaml:"removed_rules"`
+
+	// ChangedRatios represents the rules of both reports whose number of selectors without a result value or selectors changed
+	ChangedRatios []RatioChange `json:"changed_ratios" yaml:"changed_ratios"`
+
+	// OldRatioFailedTotal represents the ratio of selectors without a result value / total amount of selectors of the old report
+	OldRatioFailedTotal float32 `json:"old_ratio_failed_total" yaml:"old_ratio_failed_total"`
+
+	// NewRatioFailedTotal represents the ratio of selectors without a result value / total amount of selectors of the new report
+	NewRatioFailedTotal float32 `json:"new_ratio_failed_total" yaml:"new_ratio_failed_total"`
+}
+
+// DiffRule identifies a rule across reports.
+type DiffRule struct {
+	// File represents the file name of the rule
+	File string `json:"file" yaml:"file"`
+
+	// Group represents the group name of the rule
+	Group string `json:"group" yaml:"group"`
+
+	// Rule represents the recording rule or alert name
+	Rule string `json:"rule" yaml:"rule"`
+}
+
+// DiffSelector identifies a selector of a rule across reports.
+type DiffSelector struct {
+	// File represents the file name of the rule
+	File string `json:"file" yaml:"file"`
+
+	// Group represents the group name of the rule
+	Group string `json:"group" yaml:"group"`
+
+	// Rule represents the recording rule or alert name
+	Rule string `json:"rule" yaml:"rule"`
+
+	// Selector represents the rule's PromQL selector
+	Selector string `json:"selector" yaml:"selector"`
+}
+
+// RatioChange represents a rule whose number of selectors without a result value or selectors changed.
+type RatioChange struct {
+	// File represents the file name of the rule
+	File string `json:"file" yaml:"file"`
+
+	// Group represents the group name of the rule
+	Group string `json:"group" yaml:"group"`
+
+	// Rule represents the recording rule or alert name
+	Rule string `json:"rule" yaml:"rule"`
+
+	// OldFailed represents the number of the rule's selectors without a result value in the old report
+	OldFailed int `json:"old_failed" yaml:"old_failed"`
+
+	// OldTotal represents the number of the rule's selectors in the old report
+	OldTotal int `json:"old_total" yaml:"old_total"`
+
+	// NewFailed represents the number of the rule's selectors without a result value in the new report
+	NewFailed int `json:"new_failed" yaml:"new_failed"`
+
+	// NewTotal represents the number of the rule's selectors in the new report
+	NewTotal int `json:"new_total" yaml:"new_total"`
+}
+
+// HasRegressions reports whether any selector is newly failing.
+func (d Diff) HasRegressions() bool {
+	return len(d.NewlyFailing) > 0
+}
+
+// diffSelectors represents the selectors of a rule, by whether they returned a result value.
+type diffSelectors struct {
+	failed  map[string]bool
+	success map[string]bool
+}
+
+func (s diffSelectors) total() int {
+	return len(s.failed) + len(s.success)
+}
+
+// diffRules aggregates the selectors of all sections of a report sharing the same file, group and rule name.
+func diffRules(r Report) map[DiffRule]diffSelectors {
+	rules := map[DiffRule]diffSelectors{}
+	for _, section := range r.Sections {
+		key := DiffRule{File: section.File, Group: section.Group, Rule: section.Name}
+		selectors, ok := rules[key]
+		if !ok {
+			selectors = diffSelectors{failed: map[string]bool{}, success: map[string]bool{}}
+			rules[key] = selectors
+		}
+		for _, s := range section.NoResults {
+			selectors.failed[s] = true
+		}
+		for _, s := range section.Results {
+			selectors.success[s] = true
+		}
+	}
+	return rules
+}
+
+// NewDiff returns the changes between the reports of an old and a new run.
+// Rules are matched by file, group and name. Selectors without a result value
+// of rules the old report lacks count as newly failing, too.
+func NewDiff(oldReport, newReport Report) Diff {
+	d := Diff{
+		NewlyFailing:        []DiffSelector{},
+		Recovered:           []DiffSelector{},
+		AddedRules:          []DiffRule{},
+		RemovedRules:        []DiffRule{},
+		ChangedRatios:       []RatioChange{},
+		OldRatioFailedTotal: oldReport.RatioFailedTotal,
+		NewRatioFailedTotal: newReport.RatioFailedTotal,
+	}
+	oldRules, newRules := diffRules(oldReport), diffRules(newReport)
+
+	for _, rule := range slices.SortedFunc(maps.Keys(newRules), compareDiffRules) {
+		selectors := newRules[rule]
+		before, ok := oldRules[rule]
+		if !ok {
+			d.AddedRules = append(d.AddedRules, rule)
+		}
+		for _, s := range slices.Sorted(maps.Keys(selectors.failed)) {
+			if !before.failed[s] {
+				d.NewlyFailing = append(d.NewlyFailing, diffSelector(rule, s))
+			}
+		}
+		if !ok {
+			continue
+		}
+		for _, s := range slices.Sorted(maps.Keys(before.failed)) {
+			if !selectors.failed[s] {
+				d.Recovered = append(d.Recovered, diffSelector(rule, s))
+			}
+		}
+		if len(before.failed) != len(selectors.failed) || before.total() != selectors.total() {
+			d.ChangedRatios = append(d.ChangedRatios, RatioChange{
+				File:      rule.File,
+				Group:     rule.Group,
+				Rule:      rule.Rule,
+				OldFailed: len(before.failed),
+				OldTotal:  before.total(),
+				NewFailed: len(selectors.failed),
+				NewTotal:  selectors.total(),
+			})
+		}
+	}
+	for _, rule := range slices.SortedFunc(maps.Keys(oldRules), compareDiffRules) {
+		if _, ok := newRules[rule]; !ok {
+			d.RemovedRules = append(d.RemovedRules, rule)
+		}
+	}
+	return d
+}
+
+func diffSelector(rule DiffRule, selector string) DiffSelector {
+	return DiffSelector{File: rule.File, Group: rule.Group, Rule: rule.Rule, Selector: selector}
+}
+
+func compareDiffRules(a, b DiffRule) int {
+	return cmp.Or(
+		cmp.Compare(a.File, b.File),
+		cmp.Compare(a.Group, b.Group),
+		cmp.Compare(a.Rule, b.Rule),
+	)
+}
+
+// ToJSON returns the diff in json format.
+func (d Diff) ToJSON() (string, error) {
+	raw, err := json.MarshalIndent(struct {
+		Diff Diff `json:"promcheck_diff"`
+	}{Diff: d}, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(raw), nil
+}
+
+// ToTree returns the diff as a tree structure in text format, with ANSI
+// color codes if useColor is set.
+func (d Diff) ToTree(useColor bool) string {
+	var res string
+	newlyFailing := newNode(fmt.Sprintf("Newly failing selectors: %d", len(d.NewlyFailing)))
+	for _, s := range d.NewlyFailing {
+		newlyFailing.AddNode(colorf(useColor, color.FgRed, "[✖] %s > %s > %s: %s", s.File, s.Group, s.Rule, s.Selector))
+	}
+	res += newlyFailing.Print()
+
+	recovered := newNode(fmt.Sprintf("Recovered selectors: %d", len(d.Recovered)))
+	for _, s := range d.Recovered {
+		recovered.AddNode(colorf(useColor, color.FgGreen, "[✔] %s > %s > %s: %s", s.File, s.Group, s.Rule, s.Selector))
+	}
+	res += recovered.Print()
+
+	added := newNode(fmt.Sprintf("Added rules: %d", len(d.AddedRules)))
+	for _, r := range d.AddedRules {
+		added.AddNode(fmt.Sprintf("%s > %s > %s", r.File, r.Group, r.Rule))
+	}
+	res += added.Print()
+
+	removed := newNode(fmt.Sprintf("Removed rules: %d", len(d.RemovedRules)))
+	for _, r := range d.RemovedRules {
+		removed.AddNode(fmt.Sprintf("%s > %s > %s", r.File, r.Group, r.Rule))
+	}
+	res += removed.Print()
+
+	ratios := newNode(fmt.Sprintf("Changed ratios: %d", len(d.ChangedRatios)))
+	for _, c := range d.ChangedRatios {
+		attr := color.FgGreen
+		if c.NewFailed*c.OldTotal > c.OldFailed*c.NewTotal {
+			attr = color.FgRed
+		}
+		ratios.AddNode(colorf(useColor, attr, "%s > %s > %s: [%d/%d] -> [%d/%d]", c.File, c.Group, c.Rule,
+			c.OldTotal-c.OldFailed, c.OldTotal, c.NewTotal-c.NewFailed, c.NewTotal))
+	}
+	res += ratios.Print()
+
+	res += fmt.Sprintf("\nNo Results/Total: %.2f%% -> %.2f%%", d.OldRatioFailedTotal, d.NewRatioFailedTotal)
+	return res
+}
+
+// ToMarkdown returns the diff in Markdown format, e.g. for pull request comments.
+func (d Diff) ToMarkdown() string {
+	var b strings.Builder
+	b.WriteString("## promcheck diff\n\n")
+	fmt.Fprintf(&b, "No Results/Total: %.2f%% -> %.2f%%\n", d.OldRatioFailedTotal, d.NewRatioFailedTotal)
+
+	writeSelectors := func(title string, selectors []DiffSelector) {
+		fmt.Fprintf(&b, "\n### %s (%d)\n\n", title, len(selectors))
+		if len(selectors) == 0 {
+			b.WriteString("None.\n")
+			return
+		}
+		b.WriteString("| File | Group | Rule | Selector |\n|------|-------|------|----------|\n")
+		for _, s := range selectors {
+			fmt.Fprintf(&b, "| %s | %s | %s | `%s` |\n", markdownCell(s.File), markdownCell(s.Group), markdownCell(s.Rule), markdownCell(s.Selector))
+		}
+	}
+	writeRules := func(title string, rules []DiffRule) {
+		fmt.Fprintf(&b, "\n### %s (%d)\n\n", title, len(rules))
+		if len(rules) == 0 {
+			b.WriteString("None.\n")
+			return
+		}
+		b.WriteString("| File | Group | Rule |\n|------|-------|------|\n")
+		for _, r := range rules {
+			fmt.Fprintf(&b, "| %s | %s | %s |\n", markdownCell(r.File), markdownCell(r.Group), markdownCell(r.Rule))
+		}
+	}
+
+	writeSelectors("Newly failing selectors", d.NewlyFailing)
+	writeSelectors("Recovered selectors", d.Recovered)
+	writeRules("Added rules", d.AddedRules)
+	writeRules("Removed rules", d.RemovedRules)
+
+	fmt.Fprintf(&b, "\n### Changed ratios (%d)\n\n", len(d.ChangedRatios))
+	if len(d.ChangedRatios) == 0 {
+		b.WriteString("None.\n")
+		return b.String()
+	}
+	b.WriteString("| File | Group | Rule | Before | After |\n|------|-------|------|--------|-------|\n")
+	for _, c := range d.ChangedRatios {
+		fmt.Fprintf(&b, "| %s | %s | %s | %d/%d | %d/%d |\n", markdownCell(c.File), markdownCell(c.Group), markdownCell(c.Rule),
+			c.OldTotal-c.OldFailed, c.OldTotal, c.NewTotal-c.NewFailed, c.NewTotal)
+	}
+	return b.String()
+}
+
+// markdownCell escapes s for use in a Markdown table cell.
+func markdownCell(s string) string {
+	return strings.ReplaceAll(s, "|", `\|`)
+}
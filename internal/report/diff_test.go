@@ -0,0 +1,94 @@
+package report
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func diffTestReports(t *testing.T) (Report, Report) {
+	t.Helper()
+	oldBuilder := NewBuilder(WithWriter(io.Discard), WithoutColor())
+	oldBuilder.AddSection("f.yaml", "g", "Stable", `up and on () foo`, nil, []string{`up`, `foo`})
+	oldBuilder.AddSection("f.yaml", "g", "Recovering", `bar or baz`, []string{`bar`, `baz`}, nil)
+	oldBuilder.AddSection("f.yaml", "g", "Removed", `gone`, []string{`gone`}, nil)
+	oldBuilder.AddSection("f.yaml", "g", "Regressing", `qux{job=~"a|b"}`, nil, []string{`qux{job=~"a|b"}`})
+
+	newBuilder := NewBuilder(WithWriter(io.Discard), WithoutColor())
+	newBuilder.AddSection("f.yaml", "g", "Stable", `up and on () foo`, nil, []string{`up`, `foo`})
+	newBuilder.AddSection("f.yaml", "g", "Recovering", `bar or baz`, []string{`baz`}, []string{`bar`})
+	newBuilder.AddSection("f.yaml", "g", "Regressing", `qux{job=~"a|b"}`, []string{`qux{job=~"a|b"}`}, nil)
+	newBuilder.AddSection("f.yaml", "g", "Added", `new_metric`, []string{`new_metric`}, nil)
+
+	var reports []Report
+	for _, b := range []*Builder{oldBuilder, newBuilder} {
+		raw, err := b.ToJSON()
+		require.NoError(t, err)
+		r, err := ReadJSON(strings.NewReader(raw))
+		require.NoError(t, err)
+		reports = append(reports, r)
+	}
+	return reports[0], reports[1]
+}
+
+func TestNewDiff(t *testing.T) {
+	oldReport, newReport := diffTestReports(t)
+	d := NewDiff(oldReport, newReport)
+
+	require.Equal(t, []DiffSelector{
+		{File: "f.yaml", Group: "g", Rule: "Added", Selector: `new_metric`},
+		{File: "f.yaml", Group: "g", Rule: "Regressing", Selector: `qux{job=~"a|b"}`},
+	}, d.NewlyFailing)
+	require.Equal(t, []DiffSelector{{File: "f.yaml", Group: "g", Rule: "Recovering", Selector: `bar`}}, d.Recovered)
+	require.Equal(t, []DiffRule{{File: "f.yaml", Group: "g", Rule: "Added"}}, d.AddedRules)
+	require.Equal(t, []DiffRule{{File: "f.yaml", Group: "g", Rule: "Removed"}}, d.RemovedRules)
+	require.Equal(t, []RatioChange{
+		{File: "f.yaml", Group: "g", Rule: "Recovering", OldFailed: 2, OldTotal: 2, NewFailed: 1, NewTotal: 2},
+		{File: "f.yaml", Group: "g", Rule: "Regressing", OldFailed: 0, OldTotal: 1, NewFailed: 1, NewTotal: 1},
+	}, d.ChangedRatios)
+	require.InDelta(t, 50, d.OldRatioFailedTotal, 0.01)
+	require.InDelta(t, 50, d.NewRatioFailedTotal, 0.01)
+	require.True(t, d.HasRegressions())
+
+	require.False(t, NewDiff(oldReport, oldReport).HasRegressions())
+}
+
+func TestDiff_Renders(t *testing.T) {
+	oldReport, newReport := diffTestReports(t)
+	d := NewDiff(oldReport, newReport)
+
+	require.Contains(t, d.ToTree(false), `Newly failing selectors: 2
+├── [✖] f.yaml > g > Added: new_metric
+└── [✖] f.yaml > g > Regressing: qux{job=~"a|b"}
+Recovered selectors: 1
+└── [✔] f.yaml > g > Recovering: bar
+Added rules: 1
+└── f.yaml > g > Added
+Removed rules: 1
+└── f.yaml > g > Removed
+Changed ratios: 2
+├── f.yaml > g > Recovering: [0/2] -> [1/2]
+└── f.yaml > g > Regressing: [1/1] -> [0/1]
+
+No Results/Total: 50.00% -> 50.00%`)
+
+	raw, err := d.ToJSON()
+	require.NoError(t, err)
+	require.Contains(t, raw, `"promcheck_diff": {`)
+	require.Contains(t, raw, `"newly_failing": [`)
+
+	md := d.ToMarkdown()
+	require.Contains(t, md, "### Newly failing selectors (2)\n\n| File | Group | Rule | Selector |")
+	require.Contains(t, md, "| f.yaml | g | Regressing | `qux{job=~\"a\\|b\"}` |", "pipes must not break table cells")
+	require.Contains(t, md, "| f.yaml | g | Recovering | 0/2 | 1/2 |")
+
+	empty := NewDiff(Report{}, Report{}).ToMarkdown()
+	require.Contains(t, empty, "### Recovered selectors (0)\n\nNone.\n")
+}
+
+func TestReadJSON_RejectsInvalidReports(t *testing.T) {
+	_, err := ReadJSON(strings.NewReader(`promcheck: {}`))
+	require.Error(t, err)
+}
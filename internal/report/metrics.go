@@ -1,49 +1,39 @@
 package report
 
 const (
-	prometheusSelectorSuccessLabel = "success"
-	prometheusSelectorFailedLabel  = "failed"
+	prometheusSelectorSuccessLabel       = "success"
+	prometheusSelectorFailedLabel        = "failed"
+	prometheusSelectorExpectedEmptyLabel = "expected_empty"
+	prometheusSelectorSuppressedLabel    = "suppressed"
 )
 
 // ToPrometheusMetrics returns the report as Prometheus metrics served by the exporter.
 func (b *Builder) ToPrometheusMetrics() error {
 	b.finalize()
-	// translate slice of Sections into a map structure
-	nodeMap := make(map[string]map[string]map[string]struct {
-		success []string
-		failed  []string
-	})
-	for _, section := range b.Report.Sections {
-		if nodeMap[section.File] == nil {
-			nodeMap[section.File] = make(map[string]map[string]struct {
-				success []string
-				failed  []string
-			})
-		}
-		if nodeMap[section.File][section.Group] == nil {
-			nodeMap[section.File][section.Group] = make(map[string]struct {
-				success []string
-				failed  []string
-			})
-		}
-
-		results := nodeMap[section.File][section.Group][section.Name]
-
-		results.success = append(results.success, section.Results...)
-		results.failed = append(results.failed, section.NoResults...)
-
-		nodeMap[section.File][section.Group][section.Name] = results
-	}
+	nodeMap := groupSections(b.Report.Sections, func(Section) bool { return true })
 
 	// update metrics
 	b.metrics.SetRulesTotal(float64(b.Report.TotalRules))
 	b.metrics.SetRuleGroupsTotal(float64(b.Report.TotalGroups))
+	b.metrics.SetRulesErrored(float64(b.Report.TotalRulesErrored))
+	b.metrics.SetRulesUnchecked(float64(b.Report.TotalRulesUnchecked))
 
 	for file, groups := range nodeMap {
 		for group, rules := range groups {
 			for rule, results := range rules {
 				b.metrics.SetSelectorsTotal(file, group, rule, prometheusSelectorFailedLabel, float64(len(results.failed)))
 				b.metrics.SetSelectorsTotal(file, group, rule, prometheusSelectorSuccessLabel, float64(len(results.success)))
+				b.metrics.SetSelectorsTotal(file, group, rule, prometheusSelectorExpectedEmptyLabel, float64(len(results.expectedEmpty)))
+				b.metrics.SetSelectorsTotal(file, group, rule, prometheusSelectorSuppressedLabel, float64(len(results.suppressed)))
+				for _, bt := range results.backtests {
+					b.metrics.SetAlertBacktest(file, group, rule, float64(bt.Firing), bt.FiringRatio)
+				}
+				for _, d := range results.details {
+					b.metrics.SetSelectorSeries(file, group, rule, d.Selector, float64(d.Series))
+					if d.LastSeen != nil {
+						b.metrics.SetSelectorLastSeen(file, group, rule, d.Selector, *d.LastSeen)
+					}
+				}
 			}
 		}
 	}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"slices"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
 )
@@ -132,8 +133,13 @@ func (p *treePrinter) printNodes(t []Tree, spaces []bool) string {
 // Builder's per-instance useColor setting instead of the fatih/color
 // package-global switch.
 func (b *Builder) colorf(attr color.Attribute, format string, a ...any) string {
+	return colorf(b.useColor, attr, format, a...)
+}
+
+// colorf renders format/a with the given color attribute if useColor is set.
+func colorf(useColor bool, attr color.Attribute, format string, a ...any) string {
 	c := color.New(attr)
-	if b.useColor {
+	if useColor {
 		c.EnableColor()
 	} else {
 		c.DisableColor()
@@ -144,35 +150,9 @@ func (b *Builder) colorf(attr color.Attribute, format string, a ...any) string {
 // ToTree returns the report as a tree structure in text format.
 func (b *Builder) ToTree() (string, error) {
 	b.finalize()
-	// translate slice of Sections into a map structure
-	nodeMap := make(map[string]map[string]map[string]struct {
-		success []string
-		failed  []string
+	nodeMap := groupSections(b.Report.Sections, func(s Section) bool {
+		return !b.onlyFailing || s.failing()
 	})
-	for _, section := range b.Report.Sections {
-		if b.onlyFailing && len(section.NoResults) == 0 {
-			continue
-		}
-		if nodeMap[section.File] == nil {
-			nodeMap[section.File] = make(map[string]map[string]struct {
-				success []string
-				failed  []string
-			})
-		}
-		if nodeMap[section.File][section.Group] == nil {
-			nodeMap[section.File][section.Group] = make(map[string]struct {
-				success []string
-				failed  []string
-			})
-		}
-
-		results := nodeMap[section.File][section.Group][section.Name]
-
-		results.success = append(results.success, section.Results...)
-		results.failed = append(results.failed, section.NoResults...)
-
-		nodeMap[section.File][section.Group][section.Name] = results
-	}
 
 	// finally build the tree, walking the maps in sorted key order so the
 	// output is stable across runs (map iteration order is not).
@@ -206,16 +186,37 @@ func (b *Builder) ToTree() (string, error) {
 					rule,
 				)
 				ruleNode := newNode(prefixedRule)
+				for _, err := range results.errors {
+					ruleNode.AddNode(b.colorf(color.FgRed, "error: %s", err))
+				}
+				if results.unchecked {
+					ruleNode.AddNode(b.colorf(color.FgRed, "%s", "not checked: run deadline exceeded"))
+				}
+				for _, evaluation := range results.evaluations {
+					b.addStatusNode(ruleNode, evaluation)
+				}
+				for _, backtest := range results.backtests {
+					b.addBacktestNode(ruleNode, backtest)
+				}
+				for _, join := range results.joins {
+					b.addEmptyJoinNode(ruleNode, join)
+				}
 
 				// tree dept 4: selectors
 				for _, i := range results.success {
-					prefixedSuccess := b.colorf(color.FgGreen, "%s %s", "[✔]", i)
-					ruleNode.AddNode(prefixedSuccess)
+					b.addSuccessNode(ruleNode, results, i)
+				}
+
+				for _, i := range results.expectedEmpty {
+					ruleNode.AddNode(b.colorf(color.FgCyan, "%s %s %s", "[○]", i, "(expected to be empty at times)"))
+				}
+
+				for _, suppressed := range results.suppressed {
+					b.addSuppressedNode(ruleNode, suppressed)
 				}
 
 				for _, i := range results.failed {
-					prefixedFailed := b.colorf(color.FgRed, "%s %s", "[✖]", i)
-					ruleNode.AddNode(prefixedFailed)
+					b.addFailedNode(ruleNode, rule, results, i)
 				}
 
 				groupNode.AddSubtree(ruleNode)
@@ -230,6 +231,235 @@ func (b *Builder) ToTree() (string, error) {
 	return root.Print() + b.addSummary(), nil
 }
 
+// addSuccessNode adds a selector with a result value below its rule's node, along with its findings.
+func (b *Builder) addSuccessNode(ruleNode Tree, results ruleResults, selector string) {
+	detail, _ := results.detailFor(selector)
+	check := "[✔]"
+	if detail.Series > 0 {
+		check = fmt.Sprintf("[✔ %d]", detail.Series)
+	}
+	prefixedSuccess := b.colorf(color.FgGreen, "%s %s", check, selector)
+	if detail.Presence == PresenceWindow {
+		prefixedSuccess = b.colorf(color.FgYellow, "%s %s %s", check, selector, "(within lookback window only)")
+	}
+	if detail.NotRecorded {
+		prefixedSuccess = b.colorf(color.FgYellow, "%s %s %s", check, selector, "(not recorded yet, the sources of its recording rule have results)")
+	}
+	selectorNode := ruleNode.AddNode(prefixedSuccess)
+	b.addSeriesLimitNode(selectorNode, detail)
+	for _, missing := range detail.MissingLabels {
+		selectorNode.AddNode(b.colorf(color.FgYellow, "missing label: %s (%d of %d series lack it)", missing.Label, missing.Series, missing.Total))
+	}
+	for _, alternative := range detail.DeadAlternatives {
+		selectorNode.AddNode(b.colorf(color.FgYellow, "dead alternative: %s", alternative))
+	}
+	if detail.Stale {
+		b.addLastSeenNode(selectorNode, detail)
+	}
+	b.addWarningNodes(selectorNode, detail)
+}
+
+// addFailedNode adds a selector without a result value below the node of its rule, along with its findings.
+func (b *Builder) addFailedNode(ruleNode Tree, rule string, results ruleResults, selector string) {
+	detail, ok := results.detailFor(selector)
+	prefixedFailed := b.colorf(color.FgRed, "%s %s", "[✖]", selector)
+	switch {
+	case slices.Contains(results.baselined, selector):
+		prefixedFailed = b.colorf(color.FgRed, "%s %s %s", "[✖]", selector, "(known from baseline)")
+	case detail.Presence == PresenceAbsent:
+		prefixedFailed = b.colorf(color.FgRed, "%s %s %s", "[✖]", selector, "(no result within lookback window either)")
+	}
+	selectorNode := ruleNode.AddNode(prefixedFailed)
+	if !ok {
+		return
+	}
+	b.addLastSeenNode(selectorNode, detail)
+	for _, chain := range detail.DependencyChains {
+		selectorNode.AddNode(b.colorf(color.FgRed, "chain: %s", strings.Join(slices.Concat([]string{rule}, chain), " → ")))
+	}
+	b.addDiagnosisNodes(selectorNode, detail.Diagnosis)
+	b.addWarningNodes(selectorNode, detail)
+}
+
+// addStatusNode adds the outcome of evaluating a rule's whole expression below its node.
+func (b *Builder) addStatusNode(ruleNode Tree, evaluation ruleEvaluation) {
+	switch evaluation.status {
+	case RuleStatusResults:
+		ruleNode.AddNode(b.colorf(color.FgGreen, "expression: %d series", evaluation.series))
+	case RuleStatusEmpty:
+		ruleNode.AddNode(b.colorf(color.FgYellow, "%s", "expression: no series, all selectors return results"))
+	case RuleStatusBroken:
+		ruleNode.AddNode(b.colorf(color.FgRed, "%s", "expression: no series, broken by selectors without results or joins which never match"))
+	}
+}
+
+// addBacktestNode adds how an alerting rule would have behaved over historical data below its node.
+func (b *Builder) addBacktestNode(ruleNode Tree, bt Backtest) {
+	seconds := func(s float64) time.Duration { return time.Duration(s * float64(time.Second)) }
+	backtestNode := ruleNode.AddNode(b.colorf(
+		color.FgCyan,
+		"backtest over %s: pending %d times for %s, firing %d times for %s (%.2f%%)",
+		seconds(bt.RangeSeconds),
+		bt.Pending,
+		seconds(bt.PendingSeconds),
+		bt.Firing,
+		seconds(bt.FiringSeconds),
+		bt.FiringRatio*100,
+	))
+	if bt.NeverFired {
+		backtestNode.AddNode(b.colorf(color.FgYellow, "%s", "warning: would never have fired"))
+	}
+	if bt.Continuous {
+		backtestNode.AddNode(b.colorf(color.FgYellow, "%s", "warning: would have fired nearly continuously"))
+	}
+}
+
+// addEmptyJoinNode adds a binary operation which never matches below its rule's node, along with example match keys of both sides.
+func (b *Builder) addEmptyJoinNode(ruleNode Tree, join EmptyJoin) {
+	joinNode := ruleNode.AddNode(b.colorf(color.FgRed, "empty join: %s", join.Expression))
+	joinNode.AddNode(b.colorf(color.FgRed, "left: %d match keys, e.g. %s", join.LHS, join.LHSExample))
+	joinNode.AddNode(b.colorf(color.FgRed, "right: %d match keys, e.g. %s", join.RHS, join.RHSExample))
+}
+
+// addSuppressedNode adds a selector without a result value whose finding is suppressed below its rule's node.
+func (b *Builder) addSuppressedNode(ruleNode Tree, suppressed SuppressedSelector) {
+	text := "suppressed"
+	if suppressed.Expires != nil {
+		text += " until " + suppressed.Expires.UTC().Format(time.RFC3339)
+	}
+	if suppressed.Reason != "" {
+		text += ": " + suppressed.Reason
+	}
+	ruleNode.AddNode(b.colorf(color.FgCyan, "%s %s (%s)", "[-]", suppressed.Selector, text))
+}
+
+// addSeriesLimitNode adds a warning below a selector's node if it exceeds its cardinality threshold.
+func (b *Builder) addSeriesLimitNode(selectorNode Tree, detail SelectorDetail) {
+	if detail.SeriesLimit == 0 {
+		return
+	}
+	selectorNode.AddNode(b.colorf(color.FgYellow, "warning: %d series exceed the limit of %d", detail.Series, detail.SeriesLimit))
+}
+
+// addWarningNodes adds the query warnings of a selector below its node, in red if its probe result may be incomplete.
+func (b *Builder) addWarningNodes(selectorNode Tree, detail SelectorDetail) {
+	for _, warning := range detail.Warnings {
+		attr := color.FgYellow
+		if detail.PartialResponse {
+			attr = color.FgRed
+		}
+		selectorNode.AddNode(b.colorf(attr, "query warning: %s", warning))
+	}
+}
+
+// addLastSeenNode adds the timestamp of a selector's newest sample below its
+// node if known, or the window it wasn't seen within.
+func (b *Builder) addLastSeenNode(selectorNode Tree, detail SelectorDetail) {
+	if detail.NotSeenWithinSeconds > 0 {
+		window := time.Duration(detail.NotSeenWithinSeconds * float64(time.Second))
+		selectorNode.AddNode(b.colorf(color.FgRed, "stale, not seen within: %s", window))
+		return
+	}
+	if detail.LastSeen == nil {
+		return
+	}
+	lastSeen := detail.LastSeen.UTC().Format(time.RFC3339)
+	if detail.Stale {
+		selectorNode.AddNode(b.colorf(color.FgRed, "stale, last seen: %s", lastSeen))
+		return
+	}
+	selectorNode.AddNode(b.colorf(color.FgYellow, "last seen: %s", lastSeen))
+}
+
+// addDiagnosisNodes adds the drill-down outcome of a selector without results below its node.
+func (b *Builder) addDiagnosisNodes(selectorNode Tree, diagnosis *Diagnosis) {
+	if diagnosis == nil {
+		return
+	}
+	if diagnosis.MetricMissing {
+		selectorNode.AddNode(b.colorf(color.FgRed, "%s", "metric has no series at all"))
+	}
+	for _, culprit := range diagnosis.Culprits {
+		selectorNode.AddNode(b.colorf(color.FgRed, "culprit: %s", culprit))
+	}
+	if !diagnosis.MetricMissing && len(diagnosis.Culprits) == 0 {
+		selectorNode.AddNode(b.colorf(color.FgRed, "%s", "no single matcher to blame, only the combination matches nothing"))
+	}
+	if len(diagnosis.Suggestions) > 0 {
+		selectorNode.AddNode(b.colorf(color.FgCyan, "did you mean: %s", strings.Join(diagnosis.Suggestions, ", ")))
+	}
+}
+
+// ruleResults aggregates the selectors of all sections sharing the same file, group and rule name.
+type ruleResults struct {
+	success       []string
+	failed        []string
+	expectedEmpty []string
+	suppressed    []SuppressedSelector
+	baselined     []string
+	details       []SelectorDetail
+	joins         []EmptyJoin
+	evaluations   []ruleEvaluation
+	backtests     []Backtest
+	errors        []string
+	unchecked     bool
+}
+
+// ruleEvaluation represents the outcome of evaluating a rule's whole expression.
+type ruleEvaluation struct {
+	status string
+	series int
+}
+
+// detailFor returns the additional findings for the given selector, if any.
+func (r ruleResults) detailFor(selector string) (SelectorDetail, bool) {
+	for _, d := range r.details {
+		if d.Selector == selector {
+			return d, true
+		}
+	}
+	return SelectorDetail{}, false
+}
+
+// groupSections translates the sections accepted by keep into a file -> group -> rule map structure.
+func groupSections(sections Sections, keep func(Section) bool) map[string]map[string]map[string]ruleResults {
+	nodeMap := make(map[string]map[string]map[string]ruleResults)
+	for _, section := range sections {
+		if !keep(section) {
+			continue
+		}
+		if nodeMap[section.File] == nil {
+			nodeMap[section.File] = make(map[string]map[string]ruleResults)
+		}
+		if nodeMap[section.File][section.Group] == nil {
+			nodeMap[section.File][section.Group] = make(map[string]ruleResults)
+		}
+
+		results := nodeMap[section.File][section.Group][section.Name]
+
+		results.success = append(results.success, section.Results...)
+		results.failed = append(results.failed, section.NoResults...)
+		results.expectedEmpty = append(results.expectedEmpty, section.ExpectedEmpty...)
+		results.suppressed = append(results.suppressed, section.Suppressed...)
+		results.baselined = append(results.baselined, section.Baselined...)
+		results.details = append(results.details, section.Selectors...)
+		results.joins = append(results.joins, section.EmptyJoins...)
+		if section.Backtest != nil {
+			results.backtests = append(results.backtests, *section.Backtest)
+		}
+		if section.Status != "" {
+			results.evaluations = append(results.evaluations, ruleEvaluation{status: section.Status, series: section.Series})
+		}
+		if section.Error != "" {
+			results.errors = append(results.errors, section.Error)
+		}
+		results.unchecked = results.unchecked || section.Unchecked
+
+		nodeMap[section.File][section.Group][section.Name] = results
+	}
+	return nodeMap
+}
+
 // sortedKeys returns the keys of m in sorted order, so callers can produce
 // deterministic output when iterating over a map.
 func sortedKeys[V any](m map[string]V) []string {
@@ -251,5 +481,92 @@ func (b *Builder) addSummary() string {
 		b.Report.TotalSelectorsFailed,
 		b.Report.RatioFailedTotal,
 	)
+	if b.Report.TotalRulesErrored > 0 {
+		res += fmt.Sprintf("\nRules not checked because of an error: %d", b.Report.TotalRulesErrored)
+	}
+	if b.Report.TotalRulesUnchecked > 0 {
+		res += fmt.Sprintf("\nRules not checked before the run's deadline: %d", b.Report.TotalRulesUnchecked)
+	}
+	if b.Report.TotalProbeRetries > 0 {
+		res += fmt.Sprintf("\nQueries retried after a transient error: %d", b.Report.TotalProbeRetries)
+	}
+	if b.Report.TotalSelectorsExpectedEmpty > 0 {
+		res += fmt.Sprintf("\nSelectors without results expected to be empty at times: %d", b.Report.TotalSelectorsExpectedEmpty)
+	}
+	if b.Report.TotalSelectorsSuppressed > 0 {
+		res += fmt.Sprintf("\nSelectors without results suppressed by their rule: %d", b.Report.TotalSelectorsSuppressed)
+	}
+	res += b.baselineSummary()
+	if b.Report.TotalSelectorsWindowOnly > 0 {
+		res += fmt.Sprintf("\nResults found within lookback window only: %d", b.Report.TotalSelectorsWindowOnly)
+	}
+	if b.Report.TotalSelectorsOverSeriesLimit > 0 {
+		res += fmt.Sprintf("\nSelectors exceeding their series limit: %d", b.Report.TotalSelectorsOverSeriesLimit)
+	}
+	if evaluated := b.Report.TotalRulesWithResults + b.Report.TotalRulesEmpty + b.Report.TotalRulesBroken; evaluated > 0 {
+		res += fmt.Sprintf(
+			"\nRules evaluated: %d, With results: %d, Without results: %d, Broken: %d",
+			evaluated,
+			b.Report.TotalRulesWithResults,
+			b.Report.TotalRulesEmpty,
+			b.Report.TotalRulesBroken,
+		)
+	}
+	if b.Report.TotalAlertsBacktested > 0 {
+		res += fmt.Sprintf(
+			"\nAlerts backtested: %d, Never fired: %d, Firing nearly continuously: %d",
+			b.Report.TotalAlertsBacktested,
+			b.Report.TotalAlertsNeverFired,
+			b.Report.TotalAlertsFiringContinuously,
+		)
+	}
+	if b.Report.TotalEmptyJoins > 0 {
+		res += fmt.Sprintf("\nJoins which never match: %d", b.Report.TotalEmptyJoins)
+	}
+	if b.Report.TotalSelectorsMissingLabels > 0 {
+		res += fmt.Sprintf("\nSelectors lacking labels of their grouping or matching clauses: %d", b.Report.TotalSelectorsMissingLabels)
+	}
+	if b.Report.TotalSelectorsDeadAlternatives > 0 {
+		res += fmt.Sprintf("\nSelectors with dead regex alternatives: %d", b.Report.TotalSelectorsDeadAlternatives)
+	}
+	if b.Report.TotalSelectorsWithWarnings > 0 {
+		res += fmt.Sprintf("\nSelectors with query warnings: %d", b.Report.TotalSelectorsWithWarnings)
+	}
+	if b.Report.TotalSelectorsPartialResponse > 0 {
+		res += fmt.Sprintf("\nSelectors probed from a partial response: %d", b.Report.TotalSelectorsPartialResponse)
+	}
+	res += b.dependencySummary()
+	if b.Report.TotalSelectorsStale > 0 {
+		res += fmt.Sprintf("\nStale selectors (newest sample older than max age, or none): %d", b.Report.TotalSelectorsStale)
+	}
+	return res
+}
+
+// baselineSummary returns the summary lines of the selectors known from the
+// baseline and the baseline entries which are fixed.
+func (b *Builder) baselineSummary() string {
+	var res string
+	if b.Report.TotalSelectorsBaselined > 0 {
+		res += fmt.Sprintf("\nSelectors without results known from the baseline: %d", b.Report.TotalSelectorsBaselined)
+	}
+	if len(b.Report.BaselineFixed) > 0 {
+		res += fmt.Sprintf("\nBaseline entries fixed, ready to be removed: %d", len(b.Report.BaselineFixed))
+		for _, e := range b.Report.BaselineFixed {
+			res += fmt.Sprintf("\n  %s > %s > %s: %s", e.File, e.Group, e.Rule, e.Selector)
+		}
+	}
+	return res
+}
+
+// dependencySummary returns the summary lines of the selectors referring to
+// recording rules of the run which didn't record any series yet.
+func (b *Builder) dependencySummary() string {
+	var res string
+	if b.Report.TotalSelectorsNotRecorded > 0 {
+		res += fmt.Sprintf("\nResults found via the sources of recording rules not recorded yet: %d", b.Report.TotalSelectorsNotRecorded)
+	}
+	if b.Report.TotalSelectorsWithDependencyChains > 0 {
+		res += fmt.Sprintf("\nSelectors of recording rules whose sources lack results: %d", b.Report.TotalSelectorsWithDependencyChains)
+	}
 	return res
 }